@@ -0,0 +1,137 @@
+package hush
+
+import (
+	"context"
+	"testing"
+)
+
+type benchShallow struct {
+	ID       int
+	Name     string
+	Email    string `hush:"mask"`
+	Password string `hush:"hide"`
+	Token    string `hush:"remove"`
+	Active   bool
+	Score    float64
+	Country  string
+}
+
+type benchAddress struct {
+	Street  string `hush:"mask"`
+	City    string
+	Country string
+	Zip     string `hush:"hide"`
+}
+
+type benchAccount struct {
+	ID       int
+	Currency string
+	Balance  float64 `hush:"mask"`
+	Owner    *benchShallow
+}
+
+type benchDeep struct {
+	User      benchShallow
+	Address   benchAddress
+	Accounts  []benchAccount
+	Metadata  map[string]string
+	Tags      []string `hush:"mask"`
+	Previous  *benchDeep
+	CreatedBy string
+}
+
+func newBenchShallow() benchShallow {
+	return benchShallow{
+		ID:       1,
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: "secret123",
+		Token:    "abcdef",
+		Active:   true,
+		Score:    9.5,
+		Country:  "LK",
+	}
+}
+
+func newBenchDeep() *benchDeep {
+	owner := newBenchShallow()
+	deep := &benchDeep{
+		User:     owner,
+		Address:  benchAddress{Street: "123 Main St", City: "Anytown", Country: "USA", Zip: "12345"},
+		Metadata: map[string]string{"role": "admin", "lastLogin": "2023-04-01", "plan": "pro"},
+		Tags:     []string{"a", "b", "c", "d"},
+	}
+	for i := 0; i < 10; i++ {
+		deep.Accounts = append(deep.Accounts, benchAccount{ID: i, Currency: "USD", Balance: 100.5, Owner: &owner})
+	}
+	deep.Previous = &benchDeep{User: owner, Address: deep.Address, Accounts: deep.Accounts[:2]}
+	return deep
+}
+
+func BenchmarkHushShallow(b *testing.B) {
+	h := NewHush()
+	v := newBenchShallow()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := h.Hush(ctx, v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHushDeep(b *testing.B) {
+	h := NewHush()
+	v := newBenchDeep()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := h.Hush(ctx, v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHushConcurrency(b *testing.B) {
+	large := make([]benchAccount, 2000)
+	owner := newBenchShallow()
+	for i := range large {
+		large[i] = benchAccount{ID: i, Currency: "USD", Balance: 1, Owner: &owner}
+	}
+
+	values := []struct {
+		name  string
+		value interface{}
+	}{
+		{"Shallow", newBenchShallow()},
+		{"Deep", newBenchDeep()},
+		{"LargeSlice", large},
+	}
+	modes := []struct {
+		name        string
+		concurrency int
+	}{
+		{"Auto", ConcurrencyAuto},
+		{"Sequential", ConcurrencySequential},
+		{"Goroutines8", 8},
+	}
+
+	h := NewHush()
+	ctx := context.Background()
+	for _, v := range values {
+		for _, mode := range modes {
+			b.Run(v.name+"/"+mode.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := h.Hush(ctx, v.value, WithConcurrency(mode.concurrency)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package hush
+
+import (
+	"context"
+	"sync"
+)
+
+// Concurrency modes accepted by WithConcurrency. Any value above ConcurrencySequential
+// sets the maximum number of goroutines processing a single value.
+const (
+	// ConcurrencyAuto processes values sequentially and only fans out for structs with
+	// large subtrees and for large slices and maps of composite values.
+	ConcurrencyAuto = 0
+
+	// ConcurrencySequential processes values on the calling goroutine only.
+	ConcurrencySequential = 1
+)
+
+const (
+	// autoFanOutWeight is the struct weight (see structPlan.weight) from which
+	// ConcurrencyAuto processes the fields of a struct concurrently.
+	autoFanOutWeight = 64
+
+	// autoFanOutElements is the number of composite elements from which
+	// ConcurrencyAuto processes a slice, array or map concurrently.
+	autoFanOutElements = 128
+)
+
+// newLimiter returns the semaphore bounding the goroutines of a single call, or nil
+// when the call is processed sequentially.
+func newLimiter(concurrency int) chan struct{} {
+	switch {
+	case concurrency == ConcurrencyAuto:
+		return make(chan struct{}, maxConcurrency)
+	case concurrency > ConcurrencySequential:
+		// The calling goroutine takes part in the work as well.
+		return make(chan struct{}, concurrency-1)
+	default:
+		return nil
+	}
+}
+
+// fanOutStruct reports whether the fields of a struct with the given plan are processed concurrently.
+func (o *hushOptions) fanOutStruct(plan *structPlan) bool {
+	if o.limiter == nil {
+		return false
+	}
+	return o.concurrency != ConcurrencyAuto || plan.weight >= autoFanOutWeight
+}
+
+// fanOutElements reports whether n composite elements of a slice, array or map are processed concurrently.
+func (o *hushOptions) fanOutElements(n int) bool {
+	if o.limiter == nil {
+		return false
+	}
+	return o.concurrency != ConcurrencyAuto || n >= autoFanOutElements
+}
+
+// forEach calls fn for every index in [0, n) and returns the concatenated results in index order.
+// When parallel is set, calls are handed to new goroutines while the call's limiter has free
+// slots and run on the calling goroutine otherwise, so nested fan-outs can't deadlock.
+// The context is checked before every call and its error returned once it is done.
+// When the rows are streamed (see HushIter), calls are made sequentially and their results
+// are passed on as soon as they are returned instead of being collected.
+func forEach(ctx context.Context, opts *hushOptions, n int, parallel bool, fn func(i int) ([]Field, error)) ([]Field, error) {
+	if opts.emit != nil {
+		return nil, streamEach(ctx, opts, n, fn)
+	}
+
+	results := make([][]Field, n)
+
+	if !parallel {
+		for i := 0; i < n; i++ {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+
+			result, err := fn(i)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+		}
+		return flattenResults(results), nil
+	}
+
+	errs := make([]error, n)
+	run := func(i int) {
+		select {
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			return
+		default:
+		}
+		results[i], errs[i] = fn(i)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case opts.limiter <- struct{}{}: // acquire a slot if one is free
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-opts.limiter }() // release the slot
+				run(i)
+			}(i)
+		default:
+			run(i)
+		}
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flattenResults(results), nil
+}
+
+// flattenResults concatenates the results of forEach.
+func flattenResults(results [][]Field) []Field {
+	total := 0
+	for _, result := range results {
+		total += len(result)
+	}
+
+	data := make([]Field, 0, total)
+	for _, result := range results {
+		data = append(data, result...)
+	}
+	return data
+}
@@ -0,0 +1,92 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestConcurrencyModes(t *testing.T) {
+	large := make([]benchAccount, autoFanOutElements*2)
+	owner := newBenchShallow()
+	for i := range large {
+		large[i] = benchAccount{ID: i, Currency: "USD", Owner: &owner}
+	}
+	values := map[string]interface{}{
+		"Deep":  newBenchDeep(),
+		"Large": large,
+	}
+
+	for name, value := range values {
+		t.Run(name, func(t *testing.T) {
+			want, err := NewHush().Hush(context.Background(), value, WithConcurrency(ConcurrencySequential))
+			if err != nil {
+				t.Fatalf("Hush() sequential error = %v", err)
+			}
+
+			for _, n := range []int{ConcurrencyAuto, 2, 8, 128} {
+				got, err := NewHush().Hush(context.Background(), value, WithConcurrency(n))
+				if err != nil {
+					t.Fatalf("Hush() with concurrency %d error = %v", n, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Hush() with concurrency %d differs from the sequential result", n)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrencyCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, n := range []int{ConcurrencyAuto, ConcurrencySequential, 8} {
+		if _, err := NewHush().Hush(ctx, newBenchDeep(), WithConcurrency(n)); err != context.Canceled {
+			t.Errorf("Hush() with concurrency %d error = %v, want %v", n, err, context.Canceled)
+		}
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		wantNil     bool
+		wantCap     int
+	}{
+		{"Auto", ConcurrencyAuto, false, maxConcurrency},
+		{"Sequential", ConcurrencySequential, true, 0},
+		{"Negative", -1, true, 0},
+		{"Bounded", 4, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newLimiter(tt.concurrency)
+			if (limiter == nil) != tt.wantNil {
+				t.Fatalf("newLimiter() = %v, want nil %v", limiter, tt.wantNil)
+			}
+			if limiter != nil && cap(limiter) != tt.wantCap {
+				t.Errorf("newLimiter() capacity = %d, want %d", cap(limiter), tt.wantCap)
+			}
+		})
+	}
+}
+
+func TestForEachKeepsOrder(t *testing.T) {
+	opts := &hushOptions{concurrency: 4, limiter: newLimiter(4)}
+	for _, parallel := range []bool{false, true} {
+		got, err := forEach(context.Background(), opts, 50, parallel, func(i int) ([]Field, error) {
+			return []Field{{Path: string(rune('A' + i))}}, nil
+		})
+		if err != nil {
+			t.Fatalf("forEach() error = %v", err)
+		}
+		for i, f := range got {
+			if f.Path != string(rune('A'+i)) {
+				t.Fatalf("forEach(parallel=%v) field %d = %v, want %v", parallel, i, f.Path, string(rune('A'+i)))
+			}
+		}
+	}
+}
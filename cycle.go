@@ -0,0 +1,40 @@
+package hush
+
+import "reflect"
+
+// visit is a pointer being processed, linked to the pointers enclosing it. Cycles are
+// detected by looking for a pointer among its own ancestors only, so values shared by
+// sibling fields are still processed in full.
+type visit struct {
+	ptr    uintptr
+	typ    reflect.Type
+	path   string
+	parent *visit
+
+	// copy is the sanitized copy of the pointer, which Sanitize reuses to keep the cycle.
+	copy reflect.Value
+}
+
+// newVisit returns the visit of the non-nil pointer value at path, enclosed by parent.
+func newVisit(value reflect.Value, path string, parent *visit) *visit {
+	return &visit{ptr: value.Pointer(), typ: value.Type(), path: path, parent: parent}
+}
+
+// find returns the ancestor visit of the pointer value, if the pointer is being processed.
+func (v *visit) find(value reflect.Value) (*visit, bool) {
+	ptr, typ := value.Pointer(), value.Type()
+	for ; v != nil; v = v.parent {
+		if v.ptr == ptr && v.typ == typ {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// cycleMarker is rendered in place of a pointer leading back to the value at path.
+func cycleMarker(path string) string {
+	if path == "" {
+		path = "root"
+	}
+	return "[cycle -> " + path + "]"
+}
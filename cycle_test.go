@@ -0,0 +1,116 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type cycleNode struct {
+	Name   string `hush:"mask"`
+	Parent *cycleNode
+	Child  *cycleNode
+}
+
+func TestHushCycles(t *testing.T) {
+	parent := &cycleNode{Name: "root"}
+	child := &cycleNode{Name: "leaf", Parent: parent}
+	parent.Child = child
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		options []interface{}
+		want    [][]string
+	}{
+		{
+			name:  "Back reference to the root",
+			input: parent,
+			want: [][]string{
+				{"Child.Child", "nil"},
+				{"Child.Name", "****"},
+				{"Child.Parent", "[cycle -> root]"},
+				{"Name", "****"},
+				{"Parent", "nil"},
+			},
+		},
+		{
+			name:    "Back reference with a prefix",
+			input:   child,
+			options: []interface{}{"node"},
+			want: [][]string{
+				{"node.Child", "nil"},
+				{"node.Name", "****"},
+				{"node.Parent.Child", "[cycle -> node]"},
+				{"node.Parent.Name", "****"},
+				{"node.Parent.Parent", "nil"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewHush().Hush(context.Background(), tt.input, tt.options...)
+			if err != nil {
+				t.Fatalf("Hush() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hush() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHushSharedPointer(t *testing.T) {
+	shared := &cycleNode{Name: "shared"}
+	input := struct {
+		A *cycleNode
+		B *cycleNode
+	}{A: shared, B: shared}
+
+	got, err := NewHush().Hush(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	for _, row := range got {
+		if strings.HasPrefix(row[1], "[cycle") {
+			t.Errorf("Hush() reported a cycle for a shared pointer: %v", got)
+		}
+	}
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	chain := &cycleNode{Name: "a", Child: &cycleNode{Name: "b", Child: &cycleNode{Name: "c"}}}
+
+	got, err := NewHush().Hush(context.Background(), chain, WithMaxDepth(3))
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{
+		{"Child.Child", "[max depth exceeded]"},
+		{"Child.Name", "*"},
+		{"Child.Parent", "nil"},
+		{"Name", "*"},
+		{"Parent", "nil"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hush() = %v, want %v", got, want)
+	}
+}
+
+func TestSanitizeCycles(t *testing.T) {
+	parent := &cycleNode{Name: "root"}
+	parent.Child = &cycleNode{Name: "leaf", Parent: parent}
+
+	got, err := Sanitize(context.Background(), parent)
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if got == parent || got.Child.Parent != got {
+		t.Errorf("Sanitize() = %+v, want a copy keeping the cycle", got)
+	}
+	if got.Name != "****" || got.Child.Name != "****" || parent.Name != "root" {
+		t.Errorf("Sanitize() names = %q, %q, want both masked and the input untouched", got.Name, got.Child.Name)
+	}
+}
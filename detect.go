@@ -0,0 +1,170 @@
+package hush
+
+import (
+	"math/big"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Detector recognises sensitive values in fields that carry no hush tag.
+// Values recognised by any of the detectors set with WithDetectors are masked.
+type Detector interface {
+	Detect(value string) bool
+}
+
+// DetectorFunc adapts an ordinary function to the Detector interface.
+type DetectorFunc func(value string) bool
+
+// Detect calls f(value).
+func (f DetectorFunc) Detect(value string) bool {
+	return f(value)
+}
+
+// Built-in detectors. Each of them matches the whole value, except PrivateKeyDetector
+// which matches any value containing a PEM encoded private key.
+var (
+	// EmailDetector recognises email addresses.
+	EmailDetector Detector = regexpDetector(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`)
+
+	// PhoneDetector recognises international phone numbers of 7 to 15 digits with a leading "+",
+	// such as "+94 77 123 4567", and national numbers of 10 to 15 digits written with spaces,
+	// dashes or parentheses, such as "(555) 123-4567".
+	PhoneDetector Detector = DetectorFunc(isPhoneNumber)
+
+	// IBANDetector recognises IBANs with a valid checksum, with or without spaces.
+	IBANDetector Detector = DetectorFunc(isIBAN)
+
+	// CreditCardDetector recognises card numbers of 13 to 19 digits passing the Luhn check,
+	// with or without spaces and dashes.
+	CreditCardDetector Detector = DetectorFunc(isCreditCard)
+
+	// JWTDetector recognises JSON Web Tokens.
+	JWTDetector Detector = regexpDetector(`^eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*$`)
+
+	// AWSKeyDetector recognises AWS access key IDs.
+	AWSKeyDetector Detector = regexpDetector(`^(AKIA|ASIA|ABIA|ACCA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}$`)
+
+	// PrivateKeyDetector recognises PEM encoded private keys.
+	PrivateKeyDetector Detector = DetectorFunc(isPrivateKey)
+
+	// IPv4Detector recognises IPv4 addresses.
+	IPv4Detector Detector = DetectorFunc(func(value string) bool {
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is4()
+	})
+
+	// IPv6Detector recognises IPv6 addresses.
+	IPv6Detector Detector = DetectorFunc(func(value string) bool {
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is6()
+	})
+)
+
+// DefaultDetectors returns all the built-in detectors.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		EmailDetector,
+		PhoneDetector,
+		IBANDetector,
+		CreditCardDetector,
+		JWTDetector,
+		AWSKeyDetector,
+		PrivateKeyDetector,
+		IPv4Detector,
+		IPv6Detector,
+	}
+}
+
+// regexpDetector returns a Detector matching values against the given pattern.
+func regexpDetector(pattern string) Detector {
+	re := regexp.MustCompile(pattern)
+	return DetectorFunc(re.MatchString)
+}
+
+// detect reports whether any of the configured detectors recognises value.
+func (o *hushOptions) detect(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, d := range o.detectors {
+		if d.Detect(value) {
+			return true
+		}
+	}
+	return false
+}
+
+var phoneRegexp = regexp.MustCompile(`^\+?[0-9(][0-9 ()\-]*[0-9]$`)
+
+func isPhoneNumber(value string) bool {
+	if !phoneRegexp.MatchString(value) {
+		return false
+	}
+	digits := countDigits(value)
+	if strings.HasPrefix(value, "+") {
+		return digits >= 7 && digits <= 15
+	}
+	return strings.ContainsAny(value, " ()-") && digits >= 10 && digits <= 15
+}
+
+var ibanRegexp = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+
+func isIBAN(value string) bool {
+	compact := strings.ReplaceAll(value, " ", "")
+	if !ibanRegexp.MatchString(compact) {
+		return false
+	}
+
+	// Move the country code and check digits to the end and convert letters to numbers.
+	rearranged := compact[4:] + compact[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func isCreditCard(value string) bool {
+	compact := strings.NewReplacer(" ", "", "-", "").Replace(value)
+	if len(compact) < 13 || len(compact) > 19 || countDigits(compact) != len(compact) {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(compact) - 1; i >= 0; i-- {
+		d := int(compact[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isPrivateKey(value string) bool {
+	start := strings.Index(value, "-----BEGIN ")
+	return start >= 0 && strings.Contains(value[start:], "PRIVATE KEY-----")
+}
+
+func countDigits(value string) int {
+	n := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] >= '0' && value[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,181 @@
+package hush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// EncryptedPrefix starts every value sealed by hush:"encrypt". The full format is
+// "enc:v1:<key id>:<base64url of nonce and ciphertext>".
+const EncryptedPrefix = "enc:v1:"
+
+// KeyProvider supplies the AES keys used to seal values tagged with hush:"encrypt".
+// Keys must be 16, 24 or 32 bytes long. The ID of the key is embedded in the sealed
+// value, so keys can be rotated while older values remain readable.
+type KeyProvider interface {
+	// CurrentKey returns the key used to seal new values and its ID.
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key with the given ID.
+	Key(id string) ([]byte, error)
+}
+
+// KeyRing is a KeyProvider holding its keys in memory.
+// New values are sealed with the key named by Current.
+type KeyRing struct {
+	Current string
+	Keys    map[string][]byte
+}
+
+// CurrentKey implements KeyProvider.
+func (kr KeyRing) CurrentKey() (string, []byte, error) {
+	key, err := kr.Key(kr.Current)
+	return kr.Current, key, err
+}
+
+// Key implements KeyProvider.
+func (kr KeyRing) Key(id string) ([]byte, error) {
+	key, ok := kr.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("hush: unknown key id %q", id)
+	}
+	return key, nil
+}
+
+// encryptedValueRegexp matches sealed values inside rendered text.
+var encryptedValueRegexp = regexp.MustCompile(regexp.QuoteMeta(EncryptedPrefix) + `[^:\s"',=]+:[A-Za-z0-9_\-]+`)
+
+// encryptValue seals value with the current key of the configured key provider.
+// Encrypted fields that can't be sealed are reported by checkHushTag; should one get
+// here anyway, it is hidden rather than returned in plaintext.
+func encryptValue(value string, opts *hushOptions) string {
+	id, aead, err := opts.currentCipher()
+	if err != nil {
+		return HiddenValue
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return HiddenValue
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(id))
+	return EncryptedPrefix + id + ":" + base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+// currentKey is the current key of a call and its cipher, resolved on first use so
+// that the key provider is asked once per call and every value is sealed with the same key.
+type currentKey struct {
+	once sync.Once
+	id   string
+	aead cipher.AEAD
+	err  error
+}
+
+// currentCipher returns the ID of the current key of the configured key provider and
+// an AES-GCM cipher for it, as resolved for the call.
+func (o *hushOptions) currentCipher() (string, cipher.AEAD, error) {
+	if o.currentKey == nil {
+		return o.resolveCipher()
+	}
+	k := o.currentKey
+	k.once.Do(func() {
+		k.id, k.aead, k.err = o.resolveCipher()
+	})
+	return k.id, k.aead, k.err
+}
+
+// resolveCipher asks the key provider for its current key and returns its ID and an
+// AES-GCM cipher for it.
+func (o *hushOptions) resolveCipher() (string, cipher.AEAD, error) {
+	if o.keyProvider == nil {
+		return "", nil, errors.New("encrypt requires a key provider, see WithKeyProvider")
+	}
+
+	id, key, err := o.keyProvider.CurrentKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("current key: %w", err)
+	}
+	if strings.ContainsAny(id, ":\t\n\r \"',=") {
+		return "", nil, fmt.Errorf("invalid key id %q", id)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", nil, fmt.Errorf("key %q: %w", id, err)
+	}
+	return id, aead, nil
+}
+
+// UnhushValue decrypts a single value sealed by hush:"encrypt".
+func UnhushValue(value string, kp KeyProvider) (string, error) {
+	rest, ok := strings.CutPrefix(value, EncryptedPrefix)
+	if !ok {
+		return "", errors.New("hush: value is not encrypted")
+	}
+
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", errors.New("hush: malformed encrypted value")
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("hush: malformed encrypted value: %w", err)
+	}
+
+	key, err := kp.Key(id)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("hush: malformed encrypted value")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("hush: decrypting value with key %q: %w", id, err)
+	}
+	return string(plaintext), nil
+}
+
+// Unhush decrypts every value sealed by hush:"encrypt" in a rendered result, such as
+// the output of a Renderer or a log line, and returns the text with the plaintext
+// values in their place. Plaintext values are inserted verbatim, without escaping.
+func Unhush(rendered string, kp KeyProvider) (string, error) {
+	var firstErr error
+	result := encryptedValueRegexp.ReplaceAllStringFunc(rendered, func(sealed string) string {
+		plaintext, err := UnhushValue(sealed, kp)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return sealed
+		}
+		return plaintext
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+// newAEAD returns an AES-GCM cipher for key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
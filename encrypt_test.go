@@ -0,0 +1,170 @@
+package hush
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func testKeyRing(current string) KeyRing {
+	return KeyRing{
+		Current: current,
+		Keys: map[string][]byte{
+			"2023": bytes.Repeat([]byte{1}, 32),
+			"2024": bytes.Repeat([]byte{2}, 16),
+		},
+	}
+}
+
+func TestEncryptValue(t *testing.T) {
+	opts := &hushOptions{keyProvider: testKeyRing("2024")}
+
+	sealed := encryptValue("john@example.com", opts)
+	if !strings.HasPrefix(sealed, EncryptedPrefix+"2024:") {
+		t.Fatalf("encryptValue() = %v, want prefix %v", sealed, EncryptedPrefix+"2024:")
+	}
+	if again := encryptValue("john@example.com", opts); again == sealed {
+		t.Errorf("encryptValue() returned the same ciphertext twice, want a fresh nonce")
+	}
+
+	// Values sealed with an older key stay readable after rotation.
+	got, err := UnhushValue(sealed, testKeyRing("2023"))
+	if err != nil || got != "john@example.com" {
+		t.Errorf("UnhushValue() = %v, %v, want %v, nil", got, err, "john@example.com")
+	}
+
+	tests := []struct {
+		name string
+		opts *hushOptions
+	}{
+		{"No key provider", &hushOptions{}},
+		{"Unknown current key", &hushOptions{keyProvider: testKeyRing("2025")}},
+		{"Invalid key size", &hushOptions{keyProvider: KeyRing{Current: "bad", Keys: map[string][]byte{"bad": []byte("short")}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encryptValue("secret", tt.opts); got != HiddenValue {
+				t.Errorf("encryptValue() = %v, want %v", got, HiddenValue)
+			}
+		})
+	}
+}
+
+func TestUnhushValueErrors(t *testing.T) {
+	sealed := encryptValue("secret", &hushOptions{keyProvider: testKeyRing("2024")})
+	wrongKey := KeyRing{Keys: map[string][]byte{"2024": bytes.Repeat([]byte{3}, 16)}}
+
+	tests := []struct {
+		name  string
+		value string
+		kp    KeyProvider
+	}{
+		{"Not encrypted", "secret", testKeyRing("2024")},
+		{"Malformed", EncryptedPrefix + "2024", testKeyRing("2024")},
+		{"Bad encoding", EncryptedPrefix + "2024:!!", testKeyRing("2024")},
+		{"Unknown key", sealed, KeyRing{}},
+		{"Wrong key", sealed, wrongKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnhushValue(tt.value, tt.kp); err == nil {
+				t.Errorf("UnhushValue() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestHushEncryptAndUnhush(t *testing.T) {
+	type account struct {
+		Name  string
+		Email string `hush:"encrypt"`
+		Phone string `hush:"encrypt"`
+	}
+
+	var buf bytes.Buffer
+	err := HushTo(context.Background(), &buf,
+		account{Name: "John", Email: "john@example.com", Phone: "+94 77 123 4567"},
+		WithKeyProvider(testKeyRing("2024")),
+		WithRenderer(JSONRenderer{}),
+	)
+	if err != nil {
+		t.Fatalf("HushTo() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "john@example.com") {
+		t.Fatalf("HushTo() = %v, want the email encrypted", buf.String())
+	}
+
+	got, err := Unhush(buf.String(), testKeyRing("2024"))
+	if err != nil {
+		t.Fatalf("Unhush() error = %v", err)
+	}
+	want := `{"Email":"john@example.com","Name":"John","Phone":"+94 77 123 4567"}` + "\n"
+	if got != want {
+		t.Errorf("Unhush() = %v, want %v", got, want)
+	}
+
+	if _, err := Unhush(buf.String(), KeyRing{}); err == nil {
+		t.Errorf("Unhush() without the key error = nil, want an error")
+	}
+}
+
+func TestHushEncryptErrors(t *testing.T) {
+	type account struct {
+		Email string `hush:"encrypt"`
+	}
+
+	tests := []struct {
+		name    string
+		args    []interface{}
+		wantErr string
+	}{
+		{"No key provider", nil, "hush: field Email: encrypt requires a key provider, see WithKeyProvider"},
+		{"Unknown current key", []interface{}{WithKeyProvider(testKeyRing("2025"))}, `hush: field Email: current key: hush: unknown key id "2025"`},
+		{"Invalid key size", []interface{}{WithKeyProvider(KeyRing{Current: "bad", Keys: map[string][]byte{"bad": []byte("short")}})}, `hush: field Email: key "bad": crypto/aes: invalid key size 5`},
+		{"Invalid key id", []interface{}{WithKeyProvider(KeyRing{Current: "a:b", Keys: map[string][]byte{"a:b": bytes.Repeat([]byte{1}, 16)}})}, `hush: field Email: invalid key id "a:b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHush().Hush(context.Background(), account{Email: "john@example.com"}, tt.args...)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Hush() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// rotatingKeys is a KeyProvider moving to the next of its keys on every CurrentKey call.
+type rotatingKeys struct {
+	KeyRing
+	calls int
+}
+
+func (r *rotatingKeys) CurrentKey() (string, []byte, error) {
+	r.calls++
+	r.Current = []string{"2023", "2024"}[r.calls%2]
+	return r.KeyRing.CurrentKey()
+}
+
+func TestHushEncryptResolvesKeyOnce(t *testing.T) {
+	input := struct {
+		Email  string   `hush:"encrypt"`
+		Phone  string   `hush:"encrypt"`
+		Emails []string `hush:"encrypt"`
+	}{"john@example.com", "555-0100", []string{"a@example.com", "b@example.com"}}
+
+	keys := &rotatingKeys{KeyRing: testKeyRing("")}
+	got, err := NewHush().Hush(context.Background(), input, WithKeyProvider(keys))
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	if keys.calls != 1 {
+		t.Errorf("CurrentKey() called %d times, want 1", keys.calls)
+	}
+	for _, row := range got {
+		if !strings.HasPrefix(row[1], EncryptedPrefix+"2024:") {
+			t.Errorf("Hush() %s = %v, want it sealed with key 2024", row[0], row[1])
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefaultEnvRules are the rules HushEnv applies to environment variable names before the
+// rules passed as arguments. They hide variables whose name contains PASSWORD, SECRET or
+// TOKEN, such as PGPASSWORD, or ends in _PWD, _KEY or _DSN, such as MYSQL_PWD, matching
+// case-insensitively. The working directory in PWD and OLDPWD is kept.
+//
+// Pass them along with WithEnvNames to apply them to structs populated from the
+// environment:
+//
+//	husher.Hush(ctx, cfg, hush.WithEnvNames(true), hush.DefaultEnvRules)
+var DefaultEnvRules = []Rule{
+	{Path: "*PASSWORD*", Type: TagHide},
+	{Path: "*_PWD", Type: TagHide},
+	{Path: "*SECRET*", Type: TagHide},
+	{Path: "*TOKEN*", Type: TagHide},
+	{Path: "*_KEY", Type: TagHide},
+	{Path: "*_DSN", Type: TagHide},
+}
+
+// HushEnv hushes environment variables given as "KEY=VALUE" pairs, as returned by
+// os.Environ, and returns a row of the name and hushed value of every variable.
+//
+// It accepts the same arguments as Husher.Hush. Variables are hushed by the first of:
+//   - the rules passed as arguments, then DefaultEnvRules, matched against the name
+//   - the password of a DSN or URL in the value, e.g. "postgres://app:secret@db/app",
+//     "host=db password=secret" or "app:secret@tcp(db:3306)/app", which is hidden
+//     while the rest of the value is kept
+//   - the detectors set with WithDetectors, matched against the value
+//
+// Rows are sorted by name unless WithOrder says otherwise.
+func HushEnv(ctx context.Context, environ []string, args ...interface{}) ([][]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	opts := newHushOptions(append([]interface{}{DefaultEnvRules}, args...))
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	fields := make([]Field, 0, len(environ))
+	for _, kv := range environ {
+		name, value := splitEnv(kv)
+		path := buildFieldName(opts.prefix, escapeFieldName(name, opts.separator), opts.separator)
+
+		o := origin{tag: opts.matchRule(path), source: SourceRule}
+		if err := opts.checkHushTag(path, o.tag); err != nil {
+			return nil, err
+		}
+		if tagAction(o.tag) == TagRemove {
+			continue
+		}
+
+		if o.tag == "" && opts.hushType == "" {
+			if hushed, ok := hushDSN(value, opts); ok {
+				fields = append(fields, newField(path, hushed, reflect.ValueOf(value), string(TagHide), SourceDetector, false))
+				continue
+			}
+		}
+		fields = append(fields, processString(path, value, reflect.ValueOf(value), o, opts)...)
+	}
+
+	if opts.order.sortsRows() {
+		sortFields(fields, opts.order)
+	}
+	return fieldRows(fields), nil
+}
+
+// splitEnv splits a "KEY=VALUE" pair. A leading "=", as in the per-drive variables of
+// Windows such as "=C:=C:\", is part of the name.
+func splitEnv(kv string) (string, string) {
+	if kv == "" {
+		return "", ""
+	}
+	i := strings.IndexByte(kv[1:], '=')
+	if i < 0 {
+		return kv, ""
+	}
+	return kv[:i+1], kv[i+2:]
+}
+
+var (
+	// dsnPasswordRegexp matches the password of key/value DSNs, such as
+	// "host=db password=secret" or "Server=db;User Id=app;Password=secret".
+	dsnPasswordRegexp = regexp.MustCompile(`(?i)(^|[\s;])(password|pwd)=([^\s;]+)`)
+
+	// mysqlDSNRegexp matches the credentials of Go MySQL driver DSNs, such as
+	// "app:secret@tcp(db:3306)/app".
+	mysqlDSNRegexp = regexp.MustCompile(`^([^:/@\s]+):([^@\s]+)@[a-z]*\(`)
+)
+
+// hushDSN returns value with the password it holds hidden, if it is a DSN or a URL with
+// a password.
+func hushDSN(value string, opts *hushOptions) (string, bool) {
+	hide := func(password string) string {
+		return applyHushTag(password, string(TagHide), opts)
+	}
+
+	if scheme := strings.Index(value, "://"); scheme > 0 {
+		rest := value[scheme+3:]
+		if end := strings.IndexAny(rest, "/?#"); end >= 0 {
+			rest = rest[:end]
+		}
+		at := strings.LastIndexByte(rest, '@')
+		if at < 0 {
+			return value, false
+		}
+		colon := strings.IndexByte(rest[:at], ':')
+		if colon < 0 || colon == at-1 {
+			return value, false
+		}
+		start := scheme + 3 + colon + 1
+		end := scheme + 3 + at
+		return value[:start] + hide(value[start:end]) + value[end:], true
+	}
+
+	if m := mysqlDSNRegexp.FindStringSubmatchIndex(value); m != nil {
+		return value[:m[4]] + hide(value[m[4]:m[5]]) + value[m[5]:], true
+	}
+
+	if dsnPasswordRegexp.MatchString(value) {
+		return dsnPasswordRegexp.ReplaceAllStringFunc(value, func(match string) string {
+			m := dsnPasswordRegexp.FindStringSubmatch(match)
+			return m[1] + m[2] + "=" + hide(m[3])
+		}), true
+	}
+	return value, false
+}
+
+// envName returns the name of the environment variable a field is populated from, read
+// from its env tag, as used by github.com/caarlos0/env, or its envconfig tag, as used by
+// github.com/kelseyhightower/envconfig.
+func envName(tag reflect.StructTag) string {
+	name, ok := tag.Lookup("env")
+	if !ok {
+		name = tag.Get("envconfig")
+	}
+	name, _, _ = strings.Cut(name, ",")
+	return name
+}
@@ -0,0 +1,193 @@
+package hush
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// hushedError is an error whose message is hushed, wrapping the original error.
+type hushedError struct {
+	err error
+	msg string
+}
+
+// Error implements error.
+func (e *hushedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+func (e *hushedError) Unwrap() error {
+	return e.err
+}
+
+// Error returns err with the values recognised by the detectors in its message masked,
+// or nil if err is nil. The original error is kept in the chain, so errors.Is and
+// errors.As see through it.
+//
+// It accepts the same arguments as Husher.Hush. The detectors default to
+// DefaultDetectors unless WithDetectors sets others, and a HushType argument replaces
+// masking, e.g. TagHide. Messages are scanned word by word, see ScanError.
+func Error(err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &hushedError{err: err, msg: hushMessage(err.Error(), errorOptions(args), nil)}
+}
+
+// Errorf formats like fmt.Errorf, but runs the arguments through hush first: strings,
+// including named string types, are scanned by DefaultDetectors, errors are wrapped
+// with Error and other values, such as structs, maps and Redactors, are sanitized, see
+// Sanitize. Errors passed for %w are wrapped,
+// so errors.Is and errors.As see through the result.
+//
+//	hush.Errorf("sign in of %+v failed: %w", user, err)
+//
+// Errorf takes no options: it always uses DefaultDetectors and the default masking.
+// To use others, format with fmt.Errorf and hush the result with Error, which takes them:
+//
+//	hush.Error(fmt.Errorf("sign in of %+v failed: %w", user, err), hush.WithDetectors(hush.EmailDetector))
+func Errorf(format string, a ...interface{}) error {
+	opts := errorOptions(nil)
+
+	hushed := make([]interface{}, len(a))
+	for i, arg := range a {
+		hushed[i] = hushArg(arg, opts)
+	}
+	return fmt.Errorf(format, hushed...)
+}
+
+// ScanError returns the values recognised by the detectors in the messages of err and
+// of every error it wraps, in the order they are found and without duplicates.
+// It accepts the same arguments as Error.
+//
+// Messages are scanned word by word, words being separated by spaces, quotes, brackets
+// and the characters ",;=|". Trailing ".:!?" are ignored, so a value ending a sentence
+// is still recognised. Values spanning several words, such as phone numbers written with
+// spaces, are only recognised if they make up the whole message.
+func ScanError(err error, args ...interface{}) []string {
+	opts := errorOptions(args)
+
+	var found []string
+	seen := make(map[string]bool)
+	walkErrors(err, func(err error) {
+		hushMessage(err.Error(), opts, func(value string) {
+			if !seen[value] {
+				seen[value] = true
+				found = append(found, value)
+			}
+		})
+	})
+	return found
+}
+
+// errorOptions builds the options of Error, Errorf and ScanError, which detect
+// sensitive values with DefaultDetectors unless others are set.
+func errorOptions(args []interface{}) *hushOptions {
+	opts := newHushOptions(args)
+	if len(opts.detectors) == 0 {
+		opts.detectors = DefaultDetectors()
+	}
+	return opts
+}
+
+// hushArg returns an argument of Errorf ready to be formatted.
+func hushArg(arg interface{}, opts *hushOptions) interface{} {
+	switch v := arg.(type) {
+	case nil:
+		return nil
+	case error:
+		return &hushedError{err: v, msg: hushMessage(v.Error(), opts, nil)}
+	case string:
+		return hushMessage(v, opts, nil)
+	}
+
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() == reflect.String && !hasLeafMethods(rv.Type()) {
+		// Named strings are scanned like strings, keeping their type for verbs such as %q
+		hushed := reflect.New(rv.Type()).Elem()
+		hushed.SetString(hushMessage(rv.String(), opts, nil))
+		return hushed.Interface()
+	}
+	ht := &hushType{}
+	sanitized, err := ht.sanitizeValue(context.Background(), opts.prefix, reflect.StructField{}, rv, opts, "", 0, nil)
+	if err != nil {
+		return HiddenValue // fail closed
+	}
+	return sanitized.Interface()
+}
+
+// walkErrors calls fn for err and every error it wraps, depth first.
+func walkErrors(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		walkErrors(e.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			walkErrors(err, fn)
+		}
+	}
+}
+
+// hushMessage returns msg with the values recognised by the detectors hushed, calling
+// found, if not nil, with every one of them. See ScanError for how msg is scanned.
+func hushMessage(msg string, opts *hushOptions, found func(value string)) string {
+	if opts.validate() != nil {
+		return HiddenValue // fail closed
+	}
+
+	replace := func(value string) string {
+		if found != nil {
+			found(value)
+		}
+		hushTag := string(TagMask)
+		if opts.hushType != "" {
+			hushTag = string(opts.hushType)
+		}
+		return applyHushTag(value, hushTag, opts)
+	}
+
+	if opts.detect(msg) {
+		return replace(msg)
+	}
+
+	var b strings.Builder
+	b.Grow(len(msg))
+	start := 0
+	flush := func(end int) {
+		word := msg[start:end]
+		value := word
+		if !opts.detect(value) {
+			value = strings.TrimRight(word, ".:!?")
+		}
+		if value != "" && opts.detect(value) {
+			b.WriteString(replace(value))
+			b.WriteString(word[len(value):])
+		} else {
+			b.WriteString(word)
+		}
+	}
+	for i, r := range msg {
+		if isWordBoundary(r) {
+			flush(i)
+			b.WriteRune(r)
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	flush(len(msg))
+	return b.String()
+}
+
+// isWordBoundary reports whether r separates the words of a message.
+func isWordBoundary(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("\"'`,;=|()[]{}<>", r)
+}
@@ -0,0 +1,86 @@
+package hush
+
+import "reflect"
+
+// Source tells where the hush directive applied to a field comes from.
+type Source string
+
+// Sources of the hush directive applied to a field.
+const (
+	// SourceTag is a hush struct tag, or the hush type returned by a Redactor.
+	SourceTag Source = "tag"
+	// SourceRule is a rule set with a Rule or WithFieldRules.
+	SourceRule Source = "rule"
+	// SourceDetector is one of the detectors set with WithDetectors.
+	SourceDetector Source = "detector"
+	// SourceDefault means no tag, rule or detector applied: the value is returned as is,
+	// or hushed with the hush type passed to the call.
+	SourceDefault Source = "default"
+)
+
+// Field is a single hushed value along with what is known about it.
+type Field struct {
+	// Path is the name of the field, as returned in the first column by Husher.Hush.
+	Path string
+	// Value is the hushed value.
+	Value string
+	// Action is the hush directive applied to the value, or "" if it is returned as is.
+	Action HushType
+	// Kind is the kind of the original Go value.
+	Kind reflect.Kind
+	// TypeName is the name of the original Go type, such as "string" or "*time.Time".
+	TypeName string
+	// Private is set for values held by an unexported field, directly or further up the path.
+	Private bool
+	// Source tells where Action comes from.
+	Source Source
+}
+
+// origin describes where the hush tag of a value comes from, and whether the value is
+// held by an unexported field. Values inherit the origin of the pointer, interface,
+// slice or map holding them.
+type origin struct {
+	tag     string
+	source  Source
+	private bool
+	// envPrefix is the prefix of the env names of the fields below, see WithEnvNames.
+	envPrefix string
+}
+
+// newField returns the Field of a value, hushed with the given tag, that was resolved
+// from the given source.
+func newField(path, value string, rv reflect.Value, hushTag string, source Source, private bool) Field {
+	f := Field{
+		Path:    path,
+		Value:   value,
+		Action:  tagAction(hushTag),
+		Kind:    rv.Kind(),
+		Private: private,
+		Source:  source,
+	}
+	if f.Action == "" {
+		f.Source = SourceDefault
+	}
+	if rv.IsValid() {
+		f.TypeName = rv.Type().String()
+	}
+	return f
+}
+
+// fieldRows converts fields into the rows returned by Husher.Hush. Fields without a
+// path, such as a hushed string, become single column rows.
+func fieldRows(fields []Field) [][]string {
+	if fields == nil {
+		return nil
+	}
+
+	rows := make([][]string, len(fields))
+	for i, f := range fields {
+		if f.Path == "" {
+			rows[i] = []string{f.Value}
+		} else {
+			rows[i] = []string{f.Path, f.Value}
+		}
+	}
+	return rows
+}
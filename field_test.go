@@ -0,0 +1,87 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestHushFields(t *testing.T) {
+	type account struct {
+		Number string `hush:"mask"`
+	}
+	type user struct {
+		Name     string `hush:"hide"`
+		Email    string
+		Phone    string
+		Age      int
+		Manager  *account
+		Accounts []account
+		Tags     []string `hush:"truncate,max=2"`
+		secret   account
+	}
+
+	input := user{
+		Name:     "John",
+		Email:    "john@example.com",
+		Phone:    "555",
+		Age:      30,
+		Accounts: []account{{Number: "1234"}},
+		Tags:     []string{"admin"},
+		secret:   account{Number: "9876"},
+	}
+
+	got, err := HushFields(context.Background(), input,
+		WithPrivateFields(true),
+		WithDetectors(EmailDetector),
+		Rule{Path: "Phone", Type: TagHide},
+	)
+	if err != nil {
+		t.Fatalf("HushFields() error = %v", err)
+	}
+	want := []Field{
+		{Path: "Accounts[0].Number", Value: "****", Action: TagMask, Kind: reflect.String, TypeName: "string", Source: SourceTag},
+		{Path: "Age", Value: "30", Kind: reflect.Int, TypeName: "int", Source: SourceDefault},
+		{Path: "Email", Value: "j**************m", Action: TagMask, Kind: reflect.String, TypeName: "string", Source: SourceDetector},
+		{Path: "Manager", Value: "nil", Kind: reflect.Ptr, TypeName: "*hush.account", Source: SourceDefault},
+		{Path: "Name", Value: HiddenValue, Action: TagHide, Kind: reflect.String, TypeName: "string", Source: SourceTag},
+		{Path: "Phone", Value: HiddenValue, Action: TagHide, Kind: reflect.String, TypeName: "string", Source: SourceRule},
+		{Path: "Tags[0]", Value: "ad...", Action: TagTruncate, Kind: reflect.String, TypeName: "string", Source: SourceTag},
+		{Path: "secret.Number", Value: "****", Action: TagMask, Kind: reflect.String, TypeName: "string", Private: true, Source: SourceTag},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HushFields() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHushFieldsCallHushType(t *testing.T) {
+	got, err := HushFields(context.Background(), "secret", TagHide)
+	if err != nil {
+		t.Fatalf("HushFields() error = %v", err)
+	}
+	want := []Field{{Value: HiddenValue, Action: TagHide, Kind: reflect.String, TypeName: "string", Source: SourceDefault}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HushFields() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFieldRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []Field
+		want   [][]string
+	}{
+		{"Nil", nil, nil},
+		{"Empty", []Field{}, [][]string{}},
+		{"Named", []Field{{Path: "Name", Value: "****"}}, [][]string{{"Name", "****"}}},
+		{"Unnamed", []Field{{Value: "****"}}, [][]string{{"****"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldRows(tt.fields); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
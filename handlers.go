@@ -0,0 +1,90 @@
+package hush
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TypeHandler converts a value of a registered type into the string hushed in its place.
+type TypeHandler func(v reflect.Value) string
+
+// typeHandlers holds the registered type handlers, keyed by reflect.Type.
+var typeHandlers sync.Map
+
+func init() {
+	RegisterTypeHandler(reflect.TypeOf(time.Time{}), func(v reflect.Value) string {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano)
+	})
+	RegisterTypeHandler(reflect.TypeOf(time.Duration(0)), func(v reflect.Value) string {
+		return time.Duration(v.Int()).String()
+	})
+	RegisterTypeHandler(reflect.TypeOf(net.IP{}), func(v reflect.Value) string {
+		return v.Interface().(net.IP).String()
+	})
+	RegisterTypeHandler(reflect.TypeOf(netip.Addr{}), func(v reflect.Value) string {
+		return v.Interface().(netip.Addr).String()
+	})
+	RegisterTypeHandler(reflect.TypeOf(url.URL{}), func(v reflect.Value) string {
+		u := v.Interface().(url.URL)
+		return u.Redacted()
+	})
+	RegisterTypeHandler(reflect.TypeOf(big.Int{}), func(v reflect.Value) string {
+		if v.CanAddr() {
+			return v.Addr().Interface().(*big.Int).String()
+		}
+		n := v.Interface().(big.Int)
+		return n.String()
+	})
+	RegisterTypeHandler(reflect.TypeOf([]byte(nil)), func(v reflect.Value) string {
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	})
+	RegisterTypeHandler(reflect.TypeOf(json.RawMessage(nil)), func(v reflect.Value) string {
+		return string(v.Bytes())
+	})
+}
+
+// RegisterTypeHandler registers a handler rendering values of type t as a single
+// string, which is then hushed like any other value. Handlers are consulted before
+// values are descended into, so types such as time.Time, whose fields are unexported,
+// produce a meaningful value. Registering a handler for a type replaces the previous one.
+//
+// Handlers are registered for time.Time, time.Duration, net.IP, netip.Addr, url.URL
+// (with its password redacted), big.Int, []byte (as base64) and json.RawMessage by default.
+func RegisterTypeHandler(t reflect.Type, h TypeHandler) {
+	typeHandlers.Store(t, h)
+}
+
+// lookupTypeHandler returns the handler registered for the type of v, if any.
+// Values that can't be passed to a handler, such as values of unexported fields
+// that aren't addressable, have no handler.
+func lookupTypeHandler(v reflect.Value) (TypeHandler, reflect.Value, bool) {
+	if !v.IsValid() {
+		return nil, v, false
+	}
+
+	h, ok := typeHandlers.Load(v.Type())
+	if !ok {
+		return nil, v, false
+	}
+
+	if !v.CanInterface() {
+		if !v.CanAddr() {
+			return nil, v, false
+		}
+		v = exposeField(v)
+	}
+	return h.(TypeHandler), v, true
+}
+
+// hasTypeHandler reports whether a handler is registered for t.
+func hasTypeHandler(t reflect.Type) bool {
+	_, ok := typeHandlers.Load(t)
+	return ok
+}
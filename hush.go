@@ -2,6 +2,7 @@ package hush
 
 import (
 	"context"
+	"io"
 	"reflect"
 )
 
@@ -16,12 +17,16 @@ type hushType struct{}
 
 // Constants used throughout the package
 const (
-	TagMask   HushType = "mask"
-	TagHide   HushType = "hide"
-	TagRemove HushType = "remove"
+	TagMask     HushType = "mask"
+	TagHide     HushType = "hide"
+	TagRemove   HushType = "remove"
+	TagHash     HushType = "hash"
+	TagEncrypt  HushType = "encrypt"
+	TagTruncate HushType = "truncate"
 
-	DefaultSeparator = "."
-	HiddenValue      = "HIDDEN"
+	DefaultSeparator  = "."
+	HiddenValue       = "HIDDEN"
+	DefaultHashPrefix = "tok_"
 )
 
 // NewHush creates a new Husher instance.
@@ -31,11 +36,67 @@ func NewHush() Husher {
 }
 
 func (ht *hushType) Hush(ctx context.Context, v interface{}, args ...interface{}) ([][]string, error) {
+	fields, err := ht.hush(ctx, v, newHushOptions(args))
+	if err != nil {
+		return nil, err
+	}
+	return fieldRows(fields), nil
+}
+
+// HushFields hushes v like Husher.Hush, but returns a Field for every row, telling
+// which action was applied to the value, where it comes from and the kind and type of
+// the original value. The original value itself is never exposed.
+func HushFields(ctx context.Context, v interface{}, args ...interface{}) ([]Field, error) {
+	ht := &hushType{}
+	return ht.hush(ctx, v, newHushOptions(args))
+}
+
+// HushTo hushes v like Husher.Hush and writes the result to w using the renderer set
+// with WithRenderer, or a TextRenderer if none is set.
+func HushTo(ctx context.Context, w io.Writer, v interface{}, args ...interface{}) error {
+	opts := newHushOptions(args)
+
+	ht := &hushType{}
+	fields, err := ht.hush(ctx, v, opts)
+	if err != nil {
+		return err
+	}
+
+	renderer := opts.renderer
+	if renderer == nil {
+		renderer = TextRenderer{}
+	}
+	return renderer.Render(w, fieldRows(fields), opts.separator)
+}
+
+// hush processes v with the given options and returns the resulting fields.
+func (ht *hushType) hush(ctx context.Context, v interface{}, opts *hushOptions) ([]Field, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(v)
+	var visited *visit
+	if rv.Kind() == reflect.Ptr {
+		if !rv.IsNil() {
+			visited = newVisit(rv, opts.prefix, nil)
+		}
+		rv = rv.Elem()
+	}
+
+	return ht.processValue(ctx, opts.prefix, reflect.StructField{}, rv, opts, origin{}, 0, visited)
+}
+
+// newHushOptions builds the options for a single call from the variadic arguments
+// accepted by Hush: a string sets the prefix, a HushType sets the hush type, a Rule
+// (or a slice of them) is added to the rules and an Option is applied as is.
+func newHushOptions(args []interface{}) *hushOptions {
 	opts := &hushOptions{
 		separator:      DefaultSeparator,
 		maskFunc:       defaultMaskFunc,
 		includePrivate: false,
 		prefix:         "",
+		hashPrefix:     DefaultHashPrefix,
 	}
 
 	for _, option := range args {
@@ -44,15 +105,17 @@ func (ht *hushType) Hush(ctx context.Context, v interface{}, args ...interface{}
 			opts.prefix = opt
 		case HushType:
 			opts.hushType = opt
+		case Rule:
+			opts.rules = append(opts.rules, opt)
+		case []Rule:
+			opts.rules = append(opts.rules, opt...)
 		case Option:
 			opt(opts)
 		}
 	}
+	opts.ruleSet = compileRules(opts.rules, opts.separator)
+	opts.limiter = newLimiter(opts.concurrency)
+	opts.currentKey = &currentKey{}
 
-	rv := reflect.ValueOf(v)
-	if rv.Kind() == reflect.Ptr {
-		rv = rv.Elem()
-	}
-
-	return ht.processValue(ctx, opts.prefix, reflect.StructField{}, rv, opts, "", 0)
+	return opts
 }
@@ -74,6 +74,53 @@ func TestHushType_Hush(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Hash tag",
+			input: struct {
+				UserID string `hush:"hash"`
+				Email  string `hush:"hash"`
+			}{UserID: "42", Email: "42"},
+			options: []interface{}{WithHashKey([]byte("secret")), WithHashPrefix("id_")},
+			want: [][]string{
+				{"Email", "id_" + hashValue("42", &hushOptions{hashKey: []byte("secret")})},
+				{"UserID", "id_" + hashValue("42", &hushOptions{hashKey: []byte("secret")})},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Hash tag without key",
+			input: struct {
+				UserID string `hush:"hash"`
+			}{UserID: "42"},
+			wantErr:        true,
+			wantErrMessage: "hush: field UserID: hash requires a key, see WithHashKey",
+		},
+		{
+			name:           "Hash type without key",
+			input:          "42",
+			options:        []interface{}{TagHash},
+			wantErr:        true,
+			wantErrMessage: "hush: hash requires a key, see WithHashKey",
+		},
+		{
+			name: "Interface fields",
+			input: struct {
+				Payload interface{} `hush:"hide"`
+				Extra   map[string]interface{}
+				Empty   interface{} `hush:"mask"`
+			}{
+				Payload: nestedStruct{NestedField: "nested"},
+				Extra:   map[string]interface{}{"count": 3, "tags": []interface{}{"a", nil}},
+			},
+			want: [][]string{
+				{"Empty", "nil"},
+				{"Extra[count]", "3"},
+				{"Extra[tags][0]", "a"},
+				{"Extra[tags][1]", "nil"},
+				{"Payload.NestedField", "HIDDEN"},
+			},
+			wantErr: false,
+		},
 		{
 			name:    "String",
 			input:   "sensitive",
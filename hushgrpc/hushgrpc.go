@@ -0,0 +1,263 @@
+// Package hushgrpc redacts gRPC messages with hush before they are logged.
+//
+// The interceptors returned by UnaryServerInterceptor, StreamServerInterceptor,
+// UnaryClientInterceptor and StreamClientInterceptor walk every protobuf message of a call
+// through protoreflect, redact it and pass the resulting rows on to a Logger.
+//
+// Generated protobuf structs can't carry hush tags, so fields are hushed with rules passed
+// with WithHushOptions, or with the (tlmanz.hush.action) field option declared in
+// proto/tlmanz/hush/options.proto:
+//
+//	import "tlmanz/hush/options.proto";
+//
+//	message PayRequest {
+//	  string card_number = 1 [(tlmanz.hush.action) = ACTION_MASK];
+//	}
+package hushgrpc
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tlmanz/hush"
+	"github.com/tlmanz/hush/hushgrpc/hushpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DefaultRedactedMetadata are the metadata keys hidden unless WithMetadata is set.
+var DefaultRedactedMetadata = []string{"authorization", "cookie"}
+
+// Call is a redacted gRPC call.
+//
+// The rows of a message are named after where the value comes from: "Metadata[key]" for
+// metadata, and "Request" or "Response" followed by the path of the value for messages,
+// e.g. "Request[user][password]" or "Request[items][0][sku]", with the field names of the
+// .proto file. Rules passed with WithHushOptions address them the same way, see hush.Rule.
+//
+// Unary calls are logged once they complete. Streams are logged once for every message
+// sent or received, with only Request or Response set, and once more when they end, with
+// the metadata, code, error and duration of the stream.
+type Call struct {
+	Method   string
+	Metadata []hush.Field
+	Request  []hush.Field
+	Response []hush.Field
+	Code     codes.Code
+	Duration time.Duration
+	// Err is the error returned by the call or met redacting it, if any.
+	Err error
+}
+
+// Logger receives every redacted call.
+type Logger interface {
+	LogCall(ctx context.Context, c Call)
+}
+
+// LoggerFunc adapts an ordinary function to the Logger interface.
+type LoggerFunc func(ctx context.Context, c Call)
+
+// LogCall calls f(ctx, c).
+func (f LoggerFunc) LogCall(ctx context.Context, c Call) {
+	f(ctx, c)
+}
+
+// SlogLogger returns a Logger writing every call to l at the info level, with the rows of
+// the metadata, request and response as groups of attributes.
+func SlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(ctx context.Context, c Call) {
+		attrs := []slog.Attr{
+			slog.String("method", c.Method),
+			slog.String("code", c.Code.String()),
+			slog.Duration("duration", c.Duration),
+			slog.Attr{Key: "metadata", Value: slog.GroupValue(fieldAttrs(c.Metadata)...)},
+			slog.Attr{Key: "request", Value: slog.GroupValue(fieldAttrs(c.Request)...)},
+			slog.Attr{Key: "response", Value: slog.GroupValue(fieldAttrs(c.Response)...)},
+		}
+		if c.Err != nil {
+			attrs = append(attrs, slog.String("error", c.Err.Error()))
+		}
+		l.LogAttrs(ctx, slog.LevelInfo, "grpc call", attrs...)
+	})
+}
+
+func fieldAttrs(fields []hush.Field) []slog.Attr {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.String(f.Path, f.Value)
+	}
+	return attrs
+}
+
+// Option configures the interceptors.
+type Option func(*config)
+
+type config struct {
+	metadata []string
+	hushArgs []interface{}
+	logger   Logger
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{metadata: DefaultRedactedMetadata}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = SlogLogger(slog.Default())
+	}
+	return c
+}
+
+// WithMetadata sets the metadata keys whose values are hidden, replacing
+// DefaultRedactedMetadata. Keys are case-insensitive.
+func WithMetadata(keys ...string) Option {
+	return func(c *config) {
+		c.metadata = keys
+	}
+}
+
+// WithHushOptions sets arguments passed on to hush when redacting the rows, such as
+// rules, detectors or a mask function. See hush.Husher.Hush.
+//
+// The (tlmanz.hush.action) option of a field takes precedence over the rules.
+func WithHushOptions(args ...interface{}) Option {
+	return func(c *config) {
+		c.hushArgs = args
+	}
+}
+
+// WithLogger sets the logger receiving the calls. It defaults to SlogLogger(slog.Default()).
+func WithLogger(l Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// redactMetadata returns the redacted rows of md. Values of keys set more than once are
+// joined with ", ".
+func (c *config) redactMetadata(ctx context.Context, md metadata.MD) ([]hush.Field, error) {
+	if len(md) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]string, len(md))
+	for key, v := range md {
+		values[key] = strings.Join(v, ", ")
+	}
+
+	args := make([]interface{}, 0, len(c.hushArgs)+len(c.metadata)+1)
+	args = append(args, "Metadata")
+	for _, key := range c.metadata {
+		args = append(args, hush.Rule{Path: "Metadata[" + key + "]", Type: hush.TagHide})
+	}
+	args = append(args, c.hushArgs...)
+	return hush.HushFields(ctx, values, args...)
+}
+
+// redactMessage returns the redacted rows of a message, named after prefix. Messages
+// that aren't protobuf messages are hushed as they are.
+func (c *config) redactMessage(ctx context.Context, prefix string, msg interface{}) ([]hush.Field, error) {
+	args := make([]interface{}, 0, len(c.hushArgs)+1)
+	args = append(args, prefix)
+	args = append(args, c.hushArgs...)
+
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return hush.HushFields(ctx, msg, args...)
+	}
+
+	w := &walker{}
+	v := w.message(m.ProtoReflect(), hush.Path{{Kind: hush.SegmentField, Name: prefix}})
+	for _, rule := range w.rules {
+		args = append(args, rule)
+	}
+	return hush.HushFields(ctx, v, args...)
+}
+
+// walker turns protobuf messages into maps and slices hush can walk, collecting a rule
+// for every field with a (tlmanz.hush.action) option along the way.
+type walker struct {
+	rules []hush.Rule
+}
+
+// message returns the populated fields of m keyed by their name.
+func (w *walker) message(m protoreflect.Message, path hush.Path) map[string]interface{} {
+	fields := make(map[string]interface{})
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		if fd.IsExtension() {
+			name = "[" + string(fd.FullName()) + "]"
+		}
+		fieldPath := append(path[:len(path):len(path)], hush.Segment{Kind: hush.SegmentKey, Name: name})
+		if action := fieldAction(fd); action != "" {
+			w.rules = append(w.rules, hush.Rule{Path: fieldPath.String(), Type: action})
+		}
+
+		switch {
+		case fd.IsList():
+			list := v.List()
+			items := make([]interface{}, list.Len())
+			for i := range items {
+				itemPath := append(fieldPath[:len(fieldPath):len(fieldPath)], hush.Segment{Kind: hush.SegmentIndex, Index: i})
+				items[i] = w.value(fd, list.Get(i), itemPath)
+			}
+			fields[name] = items
+		case fd.IsMap():
+			entries := make(map[string]interface{}, v.Map().Len())
+			v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				key := k.String()
+				entryPath := append(fieldPath[:len(fieldPath):len(fieldPath)], hush.Segment{Kind: hush.SegmentKey, Name: key})
+				entries[key] = w.value(fd.MapValue(), v, entryPath)
+				return true
+			})
+			fields[name] = entries
+		default:
+			fields[name] = w.value(fd, v, fieldPath)
+		}
+		return true
+	})
+	return fields
+}
+
+// value returns a single value of a field: nested messages become maps, enums their
+// name, and bytes their base64 encoding, as in the JSON mapping of protobuf.
+func (w *walker) value(fd protoreflect.FieldDescriptor, v protoreflect.Value, path hush.Path) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return w.message(v.Message(), path)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	default:
+		return v.Interface()
+	}
+}
+
+// actions maps the values of the (tlmanz.hush.action) option to hush types.
+var actions = map[hushpb.Action]hush.HushType{
+	hushpb.Action_ACTION_MASK:    hush.TagMask,
+	hushpb.Action_ACTION_HIDE:    hush.TagHide,
+	hushpb.Action_ACTION_REMOVE:  hush.TagRemove,
+	hushpb.Action_ACTION_HASH:    hush.TagHash,
+	hushpb.Action_ACTION_ENCRYPT: hush.TagEncrypt,
+}
+
+// fieldAction returns the hush type set with the (tlmanz.hush.action) option of fd, or "" if none is.
+func fieldAction(fd protoreflect.FieldDescriptor) hush.HushType {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil || !proto.HasExtension(opts, hushpb.E_Action) {
+		return ""
+	}
+	return actions[proto.GetExtension(opts, hushpb.E_Action).(hushpb.Action)]
+}
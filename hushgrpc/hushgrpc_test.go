@@ -0,0 +1,289 @@
+package hushgrpc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tlmanz/hush"
+	"github.com/tlmanz/hush/hushgrpc/hushpb"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// testFile describes the messages and service used by the tests, as protoc would for:
+//
+//	enum Status { STATUS_UNSPECIFIED = 0; ACTIVE = 1; }
+//	message Address { string street = 1 [(tlmanz.hush.action) = ACTION_MASK]; string city = 2; }
+//	message User {
+//	  string name = 1;
+//	  string card_number = 2 [(tlmanz.hush.action) = ACTION_MASK];
+//	  string password = 3 [(tlmanz.hush.action) = ACTION_HIDE];
+//	  Address address = 4;
+//	  repeated Address addresses = 5;
+//	  map<string, string> labels = 6;
+//	  Status status = 7;
+//	  bytes avatar = 8;
+//	  repeated string tokens = 9;
+//	  string pin = 10 [(tlmanz.hush.action) = ACTION_REMOVE];
+//	}
+//	service Users {
+//	  rpc Get(User) returns (User);
+//	  rpc Watch(stream User) returns (stream User);
+//	}
+var testFile = func() protoreflect.FileDescriptor {
+	action := func(a hushpb.Action) *descriptorpb.FieldOptions {
+		opts := &descriptorpb.FieldOptions{}
+		proto.SetExtension(opts, hushpb.E_Action, a)
+		return opts
+	}
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+			Options:  opts,
+		}
+		if typeName != "" {
+			fd.TypeName = proto.String(typeName)
+		}
+		return fd
+	}
+	const (
+		str  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		msg  = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+		enum = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+		byts = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+	)
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("hushgrpc/test.proto"),
+		Package: proto.String("hushgrpc.test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("street", 1, str, "", false, action(hushpb.Action_ACTION_MASK)),
+					field("city", 2, str, "", false, nil),
+				},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, str, "", false, nil),
+					field("card_number", 2, str, "", false, action(hushpb.Action_ACTION_MASK)),
+					field("password", 3, str, "", false, action(hushpb.Action_ACTION_HIDE)),
+					field("address", 4, msg, ".hushgrpc.test.Address", false, nil),
+					field("addresses", 5, msg, ".hushgrpc.test.Address", true, nil),
+					field("labels", 6, msg, ".hushgrpc.test.User.LabelsEntry", true, nil),
+					field("status", 7, enum, ".hushgrpc.test.Status", false, nil),
+					field("avatar", 8, byts, "", false, nil),
+					field("tokens", 9, str, "", true, nil),
+					field("pin", 10, str, "", false, action(hushpb.Action_ACTION_REMOVE)),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, str, "", false, nil),
+						field("value", 2, str, "", false, nil),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Users"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Get"), InputType: proto.String(".hushgrpc.test.User"), OutputType: proto.String(".hushgrpc.test.User")},
+				{Name: proto.String("Watch"), InputType: proto.String(".hushgrpc.test.User"), OutputType: proto.String(".hushgrpc.test.User"), ClientStreaming: proto.Bool(true), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}()
+
+// newUser returns a User message with the given string fields set.
+func newUser(fields map[string]string) *dynamicpb.Message {
+	m := dynamicpb.NewMessage(testFile.Messages().ByName("User"))
+	for name, value := range fields {
+		m.Set(m.Descriptor().Fields().ByName(protoreflect.Name(name)), protoreflect.ValueOfString(value))
+	}
+	return m
+}
+
+// rows returns the paths and values of fields, or nil if there are none.
+func rows(fields []hush.Field) [][]string {
+	if len(fields) == 0 {
+		return nil
+	}
+	result := make([][]string, len(fields))
+	for i, f := range fields {
+		result[i] = []string{f.Path, f.Value}
+	}
+	return result
+}
+
+func TestRedactMessage(t *testing.T) {
+	full := newUser(map[string]string{
+		"name":        "John",
+		"card_number": "4111111111111111",
+		"password":    "hunter2",
+		"pin":         "1234",
+	})
+	fields := full.Descriptor().Fields()
+	addressDesc := testFile.Messages().ByName("Address")
+	newAddress := func(street, city string) protoreflect.Value {
+		a := dynamicpb.NewMessage(addressDesc)
+		a.Set(addressDesc.Fields().ByName("street"), protoreflect.ValueOfString(street))
+		a.Set(addressDesc.Fields().ByName("city"), protoreflect.ValueOfString(city))
+		return protoreflect.ValueOfMessage(a)
+	}
+	full.Set(fields.ByName("address"), newAddress("1 Main Street", "Springfield"))
+	addresses := full.Mutable(fields.ByName("addresses")).List()
+	addresses.Append(newAddress("2 Side Street", "Shelbyville"))
+	labels := full.Mutable(fields.ByName("labels")).Map()
+	labels.Set(protoreflect.ValueOfString("team").MapKey(), protoreflect.ValueOfString("payments"))
+	full.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+	full.Set(fields.ByName("avatar"), protoreflect.ValueOfBytes([]byte("png")))
+	tokens := full.Mutable(fields.ByName("tokens")).List()
+	tokens.Append(protoreflect.ValueOfString("token-one-abc"))
+
+	tests := []struct {
+		name     string
+		msg      interface{}
+		hushArgs []interface{}
+		want     [][]string
+	}{
+		{
+			name: "Field options",
+			msg:  full,
+			want: [][]string{
+				{"Request[address][city]", "Springfield"},
+				{"Request[address][street]", "1***********t"},
+				{"Request[addresses][0][city]", "Shelbyville"},
+				{"Request[addresses][0][street]", "2***********t"},
+				{"Request[avatar]", "cG5n"},
+				{"Request[card_number]", "4**************1"},
+				{"Request[labels][team]", "payments"},
+				{"Request[name]", "John"},
+				{"Request[password]", hush.HiddenValue},
+				{"Request[status]", "ACTIVE"},
+				{"Request[tokens][0]", "token-one-abc"},
+			},
+		},
+		{
+			name: "Rules",
+			msg:  newUser(map[string]string{"name": "John", "card_number": "4111111111111111"}),
+			hushArgs: []interface{}{
+				hush.Rule{Path: "Request.name", Type: hush.TagHide},
+				hush.Rule{Path: "Request.card_number", Type: hush.TagHide},
+			},
+			want: [][]string{
+				{"Request[card_number]", "4**************1"},
+				{"Request[name]", hush.HiddenValue},
+			},
+		},
+		{
+			name:     "Rules on nested messages",
+			msg:      full,
+			hushArgs: []interface{}{hush.Rule{Path: "*.labels", Type: hush.TagHide}, hush.Rule{Path: "*tokens*", Type: hush.TagRemove}},
+			want: [][]string{
+				{"Request[address][city]", "Springfield"},
+				{"Request[address][street]", "1***********t"},
+				{"Request[addresses][0][city]", "Shelbyville"},
+				{"Request[addresses][0][street]", "2***********t"},
+				{"Request[avatar]", "cG5n"},
+				{"Request[card_number]", "4**************1"},
+				{"Request[labels][team]", hush.HiddenValue},
+				{"Request[name]", "John"},
+				{"Request[password]", hush.HiddenValue},
+				{"Request[status]", "ACTIVE"},
+			},
+		},
+		{
+			name: "Other messages",
+			msg:  map[string]string{"password": "hunter2"},
+			hushArgs: []interface{}{
+				hush.Rule{Path: "*.password", Type: hush.TagHide},
+			},
+			want: [][]string{{"Request[password]", hush.HiddenValue}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig([]Option{WithHushOptions(tt.hushArgs...)})
+			got, err := c.redactMessage(context.Background(), "Request", tt.msg)
+			if err != nil {
+				t.Fatalf("redactMessage() error = %v", err)
+			}
+			if r := rows(got); !reflect.DeepEqual(r, tt.want) {
+				t.Errorf("redactMessage() = %v, want %v", r, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactMetadata(t *testing.T) {
+	md := metadata.Pairs("authorization", "Bearer abc", "x-request-id", "42", "x-api-key", "k123", "x-request-id", "43")
+
+	tests := []struct {
+		name string
+		opts []Option
+		want [][]string
+	}{
+		{
+			name: "Defaults",
+			want: [][]string{
+				{"Metadata[authorization]", hush.HiddenValue},
+				{"Metadata[x-api-key]", "k123"},
+				{"Metadata[x-request-id]", "42, 43"},
+			},
+		},
+		{
+			name: "Keys",
+			opts: []Option{WithMetadata("X-Api-Key")},
+			want: [][]string{
+				{"Metadata[authorization]", "Bearer abc"},
+				{"Metadata[x-api-key]", hush.HiddenValue},
+				{"Metadata[x-request-id]", "42, 43"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newConfig(tt.opts).redactMetadata(context.Background(), md)
+			if err != nil {
+				t.Fatalf("redactMetadata() error = %v", err)
+			}
+			if r := rows(got); !reflect.DeepEqual(r, tt.want) {
+				t.Errorf("redactMetadata() = %v, want %v", r, tt.want)
+			}
+		})
+	}
+}
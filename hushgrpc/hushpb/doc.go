@@ -0,0 +1,5 @@
+// Package hushpb holds the Go types of tlmanz/hush/options.proto, which declares the
+// (tlmanz.hush.action) field option read by the hushgrpc interceptors.
+package hushpb
+
+//go:generate protoc -I ../proto --go_out=. --go_opt=module=github.com/tlmanz/hush/hushgrpc/hushpb tlmanz/hush/options.proto
@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: tlmanz/hush/options.proto
+
+package hushpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Action int32
+
+const (
+	Action_ACTION_UNSPECIFIED Action = 0
+	Action_ACTION_MASK        Action = 1
+	Action_ACTION_HIDE        Action = 2
+	Action_ACTION_REMOVE      Action = 3
+	Action_ACTION_HASH        Action = 4
+	Action_ACTION_ENCRYPT     Action = 5
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_MASK",
+		2: "ACTION_HIDE",
+		3: "ACTION_REMOVE",
+		4: "ACTION_HASH",
+		5: "ACTION_ENCRYPT",
+	}
+	Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_MASK":        1,
+		"ACTION_HIDE":        2,
+		"ACTION_REMOVE":      3,
+		"ACTION_HASH":        4,
+		"ACTION_ENCRYPT":     5,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_tlmanz_hush_options_proto_enumTypes[0].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_tlmanz_hush_options_proto_enumTypes[0]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_tlmanz_hush_options_proto_rawDescGZIP(), []int{0}
+}
+
+var file_tlmanz_hush_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*Action)(nil),
+		Field:         50621,
+		Name:          "tlmanz.hush.action",
+		Tag:           "varint,50621,opt,name=action,enum=tlmanz.hush.Action",
+		Filename:      "tlmanz/hush/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional tlmanz.hush.Action action = 50621;
+	E_Action = &file_tlmanz_hush_options_proto_extTypes[0]
+)
+
+var File_tlmanz_hush_options_proto protoreflect.FileDescriptor
+
+var file_tlmanz_hush_options_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x74, 0x6c, 0x6d, 0x61, 0x6e, 0x7a, 0x2f, 0x68, 0x75, 0x73, 0x68, 0x2f, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x74, 0x6c, 0x6d,
+	0x61, 0x6e, 0x7a, 0x2e, 0x68, 0x75, 0x73, 0x68, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0x7a, 0x0a, 0x06, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b,
+	0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x41, 0x53, 0x4b, 0x10, 0x01, 0x12, 0x0f, 0x0a,
+	0x0b, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x48, 0x49, 0x44, 0x45, 0x10, 0x02, 0x12, 0x11,
+	0x0a, 0x0d, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10,
+	0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x48, 0x41, 0x53, 0x48,
+	0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x45, 0x4e, 0x43,
+	0x52, 0x59, 0x50, 0x54, 0x10, 0x05, 0x3a, 0x4c, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xbd, 0x8b, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x74, 0x6c, 0x6d, 0x61, 0x6e, 0x7a,
+	0x2e, 0x68, 0x75, 0x73, 0x68, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x74, 0x6c, 0x6d, 0x61, 0x6e, 0x7a, 0x2f, 0x68, 0x75, 0x73, 0x68, 0x2f, 0x68,
+	0x75, 0x73, 0x68, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x68, 0x75, 0x73, 0x68, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tlmanz_hush_options_proto_rawDescOnce sync.Once
+	file_tlmanz_hush_options_proto_rawDescData = file_tlmanz_hush_options_proto_rawDesc
+)
+
+func file_tlmanz_hush_options_proto_rawDescGZIP() []byte {
+	file_tlmanz_hush_options_proto_rawDescOnce.Do(func() {
+		file_tlmanz_hush_options_proto_rawDescData = protoimpl.X.CompressGZIP(file_tlmanz_hush_options_proto_rawDescData)
+	})
+	return file_tlmanz_hush_options_proto_rawDescData
+}
+
+var file_tlmanz_hush_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_tlmanz_hush_options_proto_goTypes = []any{
+	(Action)(0),                       // 0: tlmanz.hush.Action
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_tlmanz_hush_options_proto_depIdxs = []int32{
+	1, // 0: tlmanz.hush.action:extendee -> google.protobuf.FieldOptions
+	0, // 1: tlmanz.hush.action:type_name -> tlmanz.hush.Action
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_tlmanz_hush_options_proto_init() }
+func file_tlmanz_hush_options_proto_init() {
+	if File_tlmanz_hush_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tlmanz_hush_options_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_tlmanz_hush_options_proto_goTypes,
+		DependencyIndexes: file_tlmanz_hush_options_proto_depIdxs,
+		EnumInfos:         file_tlmanz_hush_options_proto_enumTypes,
+		ExtensionInfos:    file_tlmanz_hush_options_proto_extTypes,
+	}.Build()
+	File_tlmanz_hush_options_proto = out.File
+	file_tlmanz_hush_options_proto_rawDesc = nil
+	file_tlmanz_hush_options_proto_goTypes = nil
+	file_tlmanz_hush_options_proto_depIdxs = nil
+}
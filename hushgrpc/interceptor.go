@@ -0,0 +1,195 @@
+package hushgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a server interceptor logging every unary call it serves,
+// along with the incoming metadata, once redacted.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		c.logUnary(ctx, info.FullMethod, md, req, resp, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor logging every unary call it makes,
+// along with the outgoing metadata, once redacted.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(opts)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		var resp interface{}
+		if err == nil {
+			resp = reply
+		}
+		c.logUnary(ctx, method, md, req, resp, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor logging every message of the
+// streams it serves, and the streams themselves once they end, once redacted.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := ss.Context()
+		err := handler(srv, &serverStream{ServerStream: ss, config: c, method: info.FullMethod})
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		c.logEnd(ctx, info.FullMethod, md, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor logging every message of the
+// streams it opens, and the streams themselves once they end, once redacted. A stream
+// ends when receiving from it fails, including with io.EOF.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	c := newConfig(opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			c.logEnd(ctx, method, md, err, time.Since(start))
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, config: c, ctx: ctx, method: method, start: start}, nil
+	}
+}
+
+// logUnary logs a unary call. resp is nil if the call failed.
+func (c *config) logUnary(ctx context.Context, method string, md metadata.MD, req, resp interface{}, err error, d time.Duration) {
+	call := Call{
+		Method:   method,
+		Code:     status.Code(err),
+		Duration: d,
+		Err:      err,
+	}
+
+	var redactErr error
+	if call.Metadata, redactErr = c.redactMetadata(ctx, md); redactErr == nil {
+		if call.Request, redactErr = c.redactMessage(ctx, "Request", req); redactErr == nil && resp != nil {
+			call.Response, redactErr = c.redactMessage(ctx, "Response", resp)
+		}
+	}
+	if call.Err == nil {
+		call.Err = redactErr
+	}
+	c.logger.LogCall(ctx, call)
+}
+
+// logMessage logs a single message of a stream, sent or received under prefix.
+func (c *config) logMessage(ctx context.Context, method, prefix string, msg interface{}) {
+	call := Call{Method: method}
+	fields, err := c.redactMessage(ctx, prefix, msg)
+	if prefix == "Request" {
+		call.Request = fields
+	} else {
+		call.Response = fields
+	}
+	call.Err = err
+	c.logger.LogCall(ctx, call)
+}
+
+// logEnd logs the end of a stream.
+func (c *config) logEnd(ctx context.Context, method string, md metadata.MD, err error, d time.Duration) {
+	call := Call{
+		Method:   method,
+		Code:     status.Code(err),
+		Duration: d,
+		Err:      err,
+	}
+
+	var redactErr error
+	if call.Metadata, redactErr = c.redactMetadata(ctx, md); call.Err == nil {
+		call.Err = redactErr
+	}
+	c.logger.LogCall(ctx, call)
+}
+
+// serverStream logs the messages of a stream served.
+type serverStream struct {
+	grpc.ServerStream
+	config *config
+	method string
+}
+
+// RecvMsg implements grpc.ServerStream.
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.config.logMessage(s.Context(), s.method, "Request", m)
+	return nil
+}
+
+// SendMsg implements grpc.ServerStream.
+func (s *serverStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.config.logMessage(s.Context(), s.method, "Response", m)
+	return nil
+}
+
+// clientStream logs the messages of a stream opened, and the stream itself once
+// receiving from it fails.
+type clientStream struct {
+	grpc.ClientStream
+	config *config
+	ctx    context.Context
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+// SendMsg implements grpc.ClientStream.
+func (s *clientStream) SendMsg(m interface{}) error {
+	if err := s.ClientStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.config.logMessage(s.ctx, s.method, "Request", m)
+	return nil
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		s.config.logMessage(s.ctx, s.method, "Response", m)
+		return nil
+	}
+
+	s.once.Do(func() {
+		endErr := err
+		if errors.Is(err, io.EOF) {
+			endErr = nil
+		}
+		md, _ := metadata.FromOutgoingContext(s.ctx)
+		s.config.logEnd(s.ctx, s.method, md, endErr, time.Since(s.start))
+	})
+	return err
+}
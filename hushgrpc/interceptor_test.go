@@ -0,0 +1,235 @@
+package hushgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/tlmanz/hush"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// usersServer serves the Users service of testFile: Get echoes the user back, or fails
+// for users named "missing", and Watch echoes every user received.
+var usersServer = grpc.ServiceDesc{
+	ServiceName: "hushgrpc.test.Users",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Get",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newUser(nil)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				user := req.(*dynamicpb.Message)
+				if user.Get(user.Descriptor().Fields().ByName("name")).String() == "missing" {
+					return nil, status.Error(codes.NotFound, "user not found")
+				}
+				return user, nil
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/hushgrpc.test.Users/Get"}
+			return interceptor(ctx, req, info, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Watch",
+		ServerStreams: true,
+		ClientStreams: true,
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			for {
+				user := newUser(nil)
+				if err := stream.RecvMsg(user); err != nil {
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+					return err
+				}
+				if err := stream.SendMsg(user); err != nil {
+					return err
+				}
+			}
+		},
+	}},
+}
+
+// recorder is a Logger keeping every call it receives.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) LogCall(ctx context.Context, c Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, c)
+}
+
+// dial starts a server with the Users service and the interceptors logging to server,
+// and returns it along with a connection to it with the interceptors logging to client.
+func dial(t *testing.T, server, client Logger) (*grpc.Server, *grpc.ClientConn) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(WithLogger(server))),
+		grpc.StreamInterceptor(StreamServerInterceptor(WithLogger(server))),
+	)
+	s.RegisterService(&usersServer, struct{}{})
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(WithLogger(client))),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(WithLogger(client))),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return s, conn
+}
+
+// callRows is a Call with its rows reduced to paths and values.
+type callRows struct {
+	Method   string
+	Metadata [][]string
+	Request  [][]string
+	Response [][]string
+	Code     codes.Code
+}
+
+func toCallRows(calls []Call) []callRows {
+	result := make([]callRows, len(calls))
+	for i, c := range calls {
+		result[i] = callRows{c.Method, rows(c.Metadata), rows(c.Request), rows(c.Response), c.Code}
+	}
+	return result
+}
+
+// withoutGRPCMetadata drops the metadata set by grpc itself, such as the user agent,
+// from the incoming metadata rows of calls.
+func withoutGRPCMetadata(calls []callRows) []callRows {
+	for i, c := range calls {
+		var md [][]string
+		for _, row := range c.Metadata {
+			if row[0] == "Metadata[authorization]" || row[0] == "Metadata[x-request-id]" {
+				md = append(md, row)
+			}
+		}
+		calls[i].Metadata = md
+	}
+	return calls
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	server, client := &recorder{}, &recorder{}
+	_, conn := dial(t, server, client)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer abc", "x-request-id", "42")
+
+	req := newUser(map[string]string{"name": "John", "card_number": "4111111111111111", "password": "hunter2"})
+	resp := newUser(nil)
+	if err := conn.Invoke(ctx, "/hushgrpc.test.Users/Get", req, resp); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if got := resp.Get(resp.Descriptor().Fields().ByName("password")).String(); got != "hunter2" {
+		t.Errorf("response password = %q, want the original value", got)
+	}
+
+	missing := newUser(map[string]string{"name": "missing"})
+	if err := conn.Invoke(ctx, "/hushgrpc.test.Users/Get", missing, newUser(nil)); status.Code(err) != codes.NotFound {
+		t.Fatalf("Invoke() error = %v, want %v", err, codes.NotFound)
+	}
+
+	user := [][]string{
+		{"Request[card_number]", "4**************1"},
+		{"Request[name]", "John"},
+		{"Request[password]", hush.HiddenValue},
+	}
+	md := [][]string{{"Metadata[authorization]", hush.HiddenValue}, {"Metadata[x-request-id]", "42"}}
+	want := []callRows{
+		{
+			Method:   "/hushgrpc.test.Users/Get",
+			Metadata: md,
+			Request:  user,
+			Response: [][]string{
+				{"Response[card_number]", "4**************1"},
+				{"Response[name]", "John"},
+				{"Response[password]", hush.HiddenValue},
+			},
+		},
+		{
+			Method:   "/hushgrpc.test.Users/Get",
+			Metadata: md,
+			Request:  [][]string{{"Request[name]", "missing"}},
+			Code:     codes.NotFound,
+		},
+	}
+
+	for name, r := range map[string]*recorder{"server": server, "client": client} {
+		if got := withoutGRPCMetadata(toCallRows(r.calls)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s calls = %+v, want %+v", name, got, want)
+		}
+		if len(r.calls) == 2 && status.Code(r.calls[1].Err) != codes.NotFound {
+			t.Errorf("%s call error = %v, want %v", name, r.calls[1].Err, codes.NotFound)
+		}
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	server, client := &recorder{}, &recorder{}
+	s, conn := dial(t, server, client)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer abc")
+
+	stream, err := conn.NewStream(ctx, &usersServer.Streams[0], "/hushgrpc.test.Users/Watch")
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+	for _, name := range []string{"John", "Jane"} {
+		if err := stream.SendMsg(newUser(map[string]string{"name": name, "password": "hunter2"})); err != nil {
+			t.Fatalf("SendMsg() error = %v", err)
+		}
+		if err := stream.RecvMsg(newUser(nil)); err != nil {
+			t.Fatalf("RecvMsg() error = %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+	if err := stream.RecvMsg(newUser(nil)); !errors.Is(err, io.EOF) {
+		t.Fatalf("RecvMsg() error = %v, want %v", err, io.EOF)
+	}
+	// The server logs the end of the stream once the handler returns, which may be after
+	// the client has seen it; stopping the server gracefully waits for the handler.
+	s.GracefulStop()
+
+	const method = "/hushgrpc.test.Users/Watch"
+	message := func(prefix, name string) [][]string {
+		return [][]string{{prefix + "[name]", name}, {prefix + "[password]", hush.HiddenValue}}
+	}
+	want := []callRows{
+		{Method: method, Request: message("Request", "John")},
+		{Method: method, Response: message("Response", "John")},
+		{Method: method, Request: message("Request", "Jane")},
+		{Method: method, Response: message("Response", "Jane")},
+		{Method: method, Metadata: [][]string{{"Metadata[authorization]", hush.HiddenValue}}},
+	}
+
+	for name, r := range map[string]*recorder{"server": server, "client": client} {
+		if got := withoutGRPCMetadata(toCallRows(r.calls)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s calls = %+v, want %+v", name, got, want)
+		}
+	}
+}
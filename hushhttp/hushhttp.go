@@ -0,0 +1,246 @@
+// Package hushhttp redacts HTTP requests and responses with hush before they are logged.
+//
+// Middleware wraps an http.Handler and Transport wraps an http.RoundTripper. Both capture
+// the headers, query parameters and JSON bodies of every exchange, redact them and pass
+// the resulting rows on to a Logger.
+package hushhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tlmanz/hush"
+)
+
+// DefaultRedactedHeaders are the headers hidden unless WithHeaders is set.
+var DefaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultMaxBodySize is the size of the largest body captured unless WithMaxBodySize is set.
+const DefaultMaxBodySize = 64 << 10
+
+// Exchange is a redacted HTTP request along with its response.
+//
+// The rows of a message are named after where the value comes from: "Header[Name]" for
+// headers, "Query[name]" for query parameters and "Body" followed by the path of the value
+// for JSON bodies, e.g. "Body[user][password]". Rules passed with WithHushOptions address
+// them the same way, see hush.Rule.
+type Exchange struct {
+	Method string
+	// URL is the URL of the request without its query, which is part of Request.
+	URL      string
+	Status   int
+	Duration time.Duration
+	Request  []hush.Field
+	Response []hush.Field
+	// Err is the error returned by the transport or met redacting the exchange, if any.
+	Err error
+}
+
+// Logger receives every redacted exchange.
+type Logger interface {
+	LogExchange(ctx context.Context, e Exchange)
+}
+
+// LoggerFunc adapts an ordinary function to the Logger interface.
+type LoggerFunc func(ctx context.Context, e Exchange)
+
+// LogExchange calls f(ctx, e).
+func (f LoggerFunc) LogExchange(ctx context.Context, e Exchange) {
+	f(ctx, e)
+}
+
+// SlogLogger returns a Logger writing every exchange to l at the info level, with the
+// rows of the request and response as groups of attributes.
+func SlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(ctx context.Context, e Exchange) {
+		attrs := []slog.Attr{
+			slog.String("method", e.Method),
+			slog.String("url", e.URL),
+			slog.Int("status", e.Status),
+			slog.Duration("duration", e.Duration),
+			slog.Attr{Key: "request", Value: slog.GroupValue(fieldAttrs(e.Request)...)},
+			slog.Attr{Key: "response", Value: slog.GroupValue(fieldAttrs(e.Response)...)},
+		}
+		if e.Err != nil {
+			attrs = append(attrs, slog.String("error", e.Err.Error()))
+		}
+		l.LogAttrs(ctx, slog.LevelInfo, "http exchange", attrs...)
+	})
+}
+
+func fieldAttrs(fields []hush.Field) []slog.Attr {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.String(f.Path, f.Value)
+	}
+	return attrs
+}
+
+// Option configures Middleware and Transport.
+type Option func(*config)
+
+type config struct {
+	headers     []string
+	queryParams []string
+	hushArgs    []interface{}
+	logger      Logger
+	maxBodySize int64
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		headers:     DefaultRedactedHeaders,
+		maxBodySize: DefaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = SlogLogger(slog.Default())
+	}
+	return c
+}
+
+// WithHeaders sets the headers whose values are hidden, replacing DefaultRedactedHeaders.
+// Header names are case-insensitive.
+func WithHeaders(names ...string) Option {
+	return func(c *config) {
+		c.headers = names
+	}
+}
+
+// WithQueryParams sets the query parameters whose values are hidden.
+func WithQueryParams(names ...string) Option {
+	return func(c *config) {
+		c.queryParams = names
+	}
+}
+
+// WithHushOptions sets arguments passed on to hush when redacting the rows, such as
+// rules for JSON bodies, detectors or a mask function. See hush.Husher.Hush.
+func WithHushOptions(args ...interface{}) Option {
+	return func(c *config) {
+		c.hushArgs = args
+	}
+}
+
+// WithLogger sets the logger receiving the exchanges. It defaults to SlogLogger(slog.Default()).
+func WithLogger(l Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// WithMaxBodySize sets the size of the largest body captured. Larger bodies are passed on
+// untouched but left out of the rows. A size of 0 or less disables capturing bodies.
+func WithMaxBodySize(n int64) Option {
+	return func(c *config) {
+		c.maxBodySize = n
+	}
+}
+
+// message is the part of a request or response that is redacted.
+type message struct {
+	Header map[string]string
+	Query  map[string]string
+}
+
+// redact returns the redacted rows of a message with the given headers, query and body.
+// Values of multi-valued headers and parameters are joined with ", ".
+func (c *config) redact(ctx context.Context, header http.Header, query url.Values, body []byte) ([]hush.Field, error) {
+	msg := message{Header: make(map[string]string, len(header))}
+	for name, values := range header {
+		msg.Header[name] = strings.Join(values, ", ")
+	}
+	if len(query) > 0 {
+		msg.Query = make(map[string]string, len(query))
+		for name, values := range query {
+			msg.Query[name] = strings.Join(values, ", ")
+		}
+	}
+
+	args := make([]interface{}, 0, len(c.hushArgs)+len(c.headers)+len(c.queryParams))
+	for _, name := range c.headers {
+		args = append(args, hush.Rule{Path: "Header[" + name + "]", Type: hush.TagHide})
+	}
+	for _, name := range c.queryParams {
+		args = append(args, hush.Rule{Path: "Query[" + name + "]", Type: hush.TagHide})
+	}
+	args = append(args, c.hushArgs...)
+
+	fields, err := hush.HushFields(ctx, msg, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil {
+			bodyFields, err := hush.HushFields(ctx, v, append([]interface{}{"Body"}, args...)...)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, bodyFields...)
+		}
+	}
+	return fields, nil
+}
+
+// isJSON reports whether the content type is JSON, such as "application/json" or
+// "application/problem+json".
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// captureBody reads the body of a message with the given content type, if it is JSON and
+// no larger than the configured size. It returns the body read, or nil, along with a
+// reader yielding the whole body again for the handler or client.
+func (c *config) captureBody(contentType string, body io.ReadCloser) ([]byte, io.ReadCloser) {
+	if !c.capturesBody(contentType, body) {
+		return nil, body
+	}
+
+	// Whatever was read is handed back, followed by the rest of the body and the read error, if any.
+	content, err := io.ReadAll(io.LimitReader(body, c.maxBodySize+1))
+	restored := readCloser{Reader: io.MultiReader(bytes.NewReader(content), body), Closer: body}
+	if err != nil || int64(len(content)) > c.maxBodySize {
+		return nil, restored
+	}
+	return content, restored
+}
+
+// capturesBody reports whether the body of a message with the given content type is
+// logged: it must be JSON and bodies must be enabled.
+func (c *config) capturesBody(contentType string, body io.ReadCloser) bool {
+	return body != nil && body != http.NoBody && c.maxBodySize > 0 && isJSON(contentType)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// urlWithoutQuery returns u without its query and fragment.
+func urlWithoutQuery(u *url.URL) string {
+	stripped := *u
+	stripped.RawQuery = ""
+	stripped.ForceQuery = false
+	stripped.Fragment = ""
+	stripped.RawFragment = ""
+	stripped.User = nil
+	return stripped.String()
+}
@@ -0,0 +1,83 @@
+package hushhttp
+
+import (
+	"net/http"
+	"time"
+)
+
+// Middleware returns a handler calling next and logging every request it serves, along
+// with the response, once redacted.
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	c := newConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		reqBody, body := c.captureBody(r.Header.Get("Content-Type"), r.Body)
+		r.Body = body
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK, limit: c.maxBodySize}
+		next.ServeHTTP(rec, r)
+
+		e := Exchange{
+			Method:   r.Method,
+			URL:      urlWithoutQuery(r.URL),
+			Status:   rec.status,
+			Duration: time.Since(start),
+		}
+		var respBody []byte
+		if !rec.overflow && isJSON(rec.Header().Get("Content-Type")) {
+			respBody = rec.body
+		}
+
+		ctx := r.Context()
+		if e.Request, e.Err = c.redact(ctx, r.Header, r.URL.Query(), reqBody); e.Err == nil {
+			e.Response, e.Err = c.redact(ctx, rec.Header(), nil, respBody)
+		}
+		c.logger.LogExchange(ctx, e)
+	})
+}
+
+// responseRecorder passes a response on while recording its status and the start of its body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	limit       int64
+	body        []byte
+	overflow    bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if !r.overflow {
+		if int64(len(r.body)+len(p)) > r.limit {
+			r.body, r.overflow = nil, true
+		} else {
+			r.body = append(r.body, p...)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		r.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,114 @@
+package hushhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tlmanz/hush"
+)
+
+// rows returns the paths and values of fields.
+func rows(fields []hush.Field) [][]string {
+	result := make([][]string, len(fields))
+	for i, f := range fields {
+		result[i] = []string{f.Path, f.Value}
+	}
+	return result
+}
+
+func TestMiddleware(t *testing.T) {
+	var got Exchange
+	var handlerBody string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"secret-token","id":7}`))
+	}),
+		WithQueryParams("api_key"),
+		WithHushOptions(hush.Rule{Path: "Body.password", Type: hush.TagHide}, hush.Rule{Path: "Body.token", Type: hush.TagMask}),
+		WithLogger(LoggerFunc(func(ctx context.Context, e Exchange) { got = e })),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/users?api_key=k123&page=2", strings.NewReader(`{"name":"John","password":"hunter2"}`))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if handlerBody != `{"name":"John","password":"hunter2"}` {
+		t.Errorf("handler read body %q, want the original body", handlerBody)
+	}
+	if rec.Code != http.StatusCreated || rec.Body.String() != `{"token":"secret-token","id":7}` {
+		t.Errorf("response = %d %q, want the original response", rec.Code, rec.Body.String())
+	}
+
+	if got.Method != http.MethodPost || got.URL != "/users" || got.Status != http.StatusCreated || got.Err != nil {
+		t.Errorf("Exchange = %+v, want POST /users 201", got)
+	}
+	wantRequest := [][]string{
+		{"Header[Authorization]", hush.HiddenValue},
+		{"Header[Content-Type]", "application/json"},
+		{"Query[api_key]", hush.HiddenValue},
+		{"Query[page]", "2"},
+		{"Body[name]", "John"},
+		{"Body[password]", hush.HiddenValue},
+	}
+	if r := rows(got.Request); !reflect.DeepEqual(r, wantRequest) {
+		t.Errorf("Exchange.Request = %v, want %v", r, wantRequest)
+	}
+	wantResponse := [][]string{
+		{"Header[Content-Type]", "application/json"},
+		{"Header[Set-Cookie]", hush.HiddenValue},
+		{"Body[id]", "7"},
+		{"Body[token]", "s**********n"},
+	}
+	if r := rows(got.Response); !reflect.DeepEqual(r, wantResponse) {
+		t.Errorf("Exchange.Response = %v, want %v", r, wantResponse)
+	}
+}
+
+func TestMiddlewareSkipsLargeAndNonJSONBodies(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"Too large", "application/json", `{"password":"a very long password"}`},
+		{"Not JSON", "text/plain", "password=hunter2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Exchange
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = io.Copy(w, r.Body)
+			}),
+				WithMaxBodySize(16),
+				WithLogger(LoggerFunc(func(ctx context.Context, e Exchange) { got = e })),
+			)
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.body {
+				t.Errorf("response body = %q, want %q", rec.Body.String(), tt.body)
+			}
+			for _, f := range append(got.Request, got.Response...) {
+				if strings.HasPrefix(f.Path, "Body") {
+					t.Errorf("Exchange has body row %s=%s, want none", f.Path, f.Value)
+				}
+			}
+		})
+	}
+}
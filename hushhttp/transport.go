@@ -0,0 +1,106 @@
+package hushhttp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Transport is an http.RoundTripper logging every request it sends, along with the
+// response, once redacted.
+type Transport struct {
+	base   http.RoundTripper
+	config *config
+}
+
+// NewTransport returns a Transport sending requests with base, or http.DefaultTransport
+// if base is nil.
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, config: newConfig(opts)}
+}
+
+// RoundTrip implements http.RoundTripper. Responses with a body that is logged are
+// passed on as they are read: the exchange is logged once the caller has read the body
+// to its end or closed it, so streamed responses aren't held back.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := t.config
+	start := time.Now()
+
+	// The request is cloned rather than modified, as required of a RoundTripper.
+	reqBody, body := c.captureBody(req.Header.Get("Content-Type"), req.Body)
+	if body != req.Body {
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	e := Exchange{
+		Method:   req.Method,
+		URL:      urlWithoutQuery(req.URL),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	ctx := req.Context()
+	var redactErr error
+	e.Request, redactErr = c.redact(ctx, req.Header, req.URL.Query(), reqBody)
+	logExchange := func(respBody []byte) {
+		if resp != nil && redactErr == nil {
+			e.Response, redactErr = c.redact(ctx, resp.Header, nil, respBody)
+		}
+		if e.Err == nil {
+			e.Err = redactErr
+		}
+		c.logger.LogExchange(ctx, e)
+	}
+
+	if resp != nil {
+		e.Status = resp.StatusCode
+		if c.capturesBody(resp.Header.Get("Content-Type"), resp.Body) {
+			resp.Body = &bodyRecorder{ReadCloser: resp.Body, limit: c.maxBodySize, done: logExchange}
+			return resp, err
+		}
+	}
+	logExchange(nil)
+	return resp, err
+}
+
+// bodyRecorder passes a response body on while recording its start, calling done once
+// the body is read to its end or closed. done gets the body only if it was read to its
+// end without exceeding limit.
+type bodyRecorder struct {
+	io.ReadCloser
+	limit    int64
+	body     []byte
+	overflow bool
+	once     sync.Once
+	done     func(body []byte)
+}
+
+// Read implements io.Reader.
+func (r *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if !r.overflow {
+		if int64(len(r.body)+n) > r.limit {
+			r.body, r.overflow = nil, true
+		} else {
+			r.body = append(r.body, p[:n]...)
+		}
+	}
+	if err == io.EOF {
+		r.once.Do(func() { r.done(r.body) })
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (r *bodyRecorder) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(func() { r.done(nil) })
+	return err
+}
@@ -0,0 +1,166 @@
+package hushhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tlmanz/hush"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	var got Exchange
+	client := &http.Client{Transport: NewTransport(nil,
+		WithHeaders("X-Api-Key"),
+		WithHushOptions(hush.Rule{Path: "Body.card", Type: hush.TagMask}),
+		WithLogger(LoggerFunc(func(ctx context.Context, e Exchange) { got = e })),
+	)}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/pay?ref=1", strings.NewReader(`{"card":"4111111111111111"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", "key")
+	req.Header.Set("Authorization", "Bearer visible")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `{"card":"4111111111111111"}` {
+		t.Errorf("response body = %q, want the original body", body)
+	}
+	if got.URL != server.URL+"/pay" || got.Status != http.StatusOK || got.Err != nil {
+		t.Errorf("Exchange = %+v, want %s/pay 200", got, server.URL)
+	}
+
+	wantRequest := [][]string{
+		{"Header[Authorization]", "Bearer visible"},
+		{"Header[Content-Type]", "application/json"},
+		{"Header[X-Api-Key]", hush.HiddenValue},
+		{"Query[ref]", "1"},
+		{"Body[card]", "4**************1"},
+	}
+	if r := rows(got.Request); !reflect.DeepEqual(r, wantRequest) {
+		t.Errorf("Exchange.Request = %v, want %v", r, wantRequest)
+	}
+	if r := rows(got.Response); len(r) == 0 || r[len(r)-1][0] != "Body[card]" || r[len(r)-1][1] != "4**************1" {
+		t.Errorf("Exchange.Response = %v, want the card masked", r)
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	wantErr := errors.New("connection refused")
+	var got Exchange
+	transport := NewTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, wantErr
+	}), WithLogger(LoggerFunc(func(ctx context.Context, e Exchange) { got = e })))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/?token=abc", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, wantErr) {
+		t.Fatalf("RoundTrip() error = %v, want %v", err, wantErr)
+	}
+	if !errors.Is(got.Err, wantErr) || got.URL != "http://example.com/" {
+		t.Errorf("Exchange = %+v, want the transport error logged", got)
+	}
+}
+
+func TestTransportStreamsResponse(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"card":`)
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = io.WriteString(w, `"4111111111111111"}`)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	logged := make(chan Exchange, 1)
+	client := &http.Client{Transport: NewTransport(nil,
+		WithHushOptions(hush.Rule{Path: "Body.card", Type: hush.TagMask}),
+		WithLogger(LoggerFunc(func(ctx context.Context, e Exchange) { logged <- e })),
+	)}
+
+	// The response is returned before the server has finished writing it.
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	start := make([]byte, len(`{"card":`))
+	if _, err := io.ReadFull(resp.Body, start); err != nil {
+		t.Fatalf("reading the start of the body: %v", err)
+	}
+	select {
+	case e := <-logged:
+		t.Fatalf("Exchange logged before the body was read: %+v", e)
+	default:
+	}
+
+	release <- struct{}{}
+	rest, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if got := string(start) + string(rest); got != `{"card":"4111111111111111"}` {
+		t.Errorf("response body = %q, want the original body", got)
+	}
+
+	e := <-logged
+	if r := rows(e.Response); len(r) == 0 || r[len(r)-1][0] != "Body[card]" || r[len(r)-1][1] != "4**************1" {
+		t.Errorf("Exchange.Response = %v, want the card masked", r)
+	}
+}
+
+func TestTransportLogsUnreadResponseOnClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"card":"4111111111111111"}`)
+	}))
+	defer server.Close()
+
+	var got []Exchange
+	client := &http.Client{Transport: NewTransport(nil,
+		WithLogger(LoggerFunc(func(ctx context.Context, e Exchange) { got = append(got, e) })),
+	)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("Exchange logged before the body was closed: %+v", got)
+	}
+	resp.Body.Close()
+	resp.Body.Close()
+
+	if len(got) != 1 || got[0].Status != http.StatusOK {
+		t.Fatalf("logged %+v, want a single exchange", got)
+	}
+	for _, row := range rows(got[0].Response) {
+		if strings.HasPrefix(row[0], "Body") {
+			t.Errorf("Exchange.Response = %v, want no body", rows(got[0].Response))
+		}
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
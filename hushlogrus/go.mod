@@ -0,0 +1,14 @@
+module github.com/tlmanz/hush/hushlogrus
+
+go 1.21.7
+
+require (
+	github.com/sirupsen/logrus v1.9.3
+	github.com/tlmanz/hush v0.2.0
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/olekukonko/tablewriter v0.0.5 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
@@ -0,0 +1,115 @@
+// Package hushlogrus hushes values logged with github.com/sirupsen/logrus.
+//
+// Hook and Formatter replace every field of an entry holding a struct with hush tags by
+// its hushed value: a tree of maps whose keys, joined by the separator, match the field
+// names returned by Hush. Use the hook to hush entries before any other hook sees them,
+// or the formatter to hush them only when they are written.
+package hushlogrus
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tlmanz/hush"
+)
+
+// Hook is a logrus.Hook hushing the fields of every entry.
+type Hook struct {
+	args []interface{}
+}
+
+// NewHook returns a hook hushing the fields of every entry. It accepts the same
+// arguments as hush.Husher.Hush.
+//
+//	logger.AddHook(hushlogrus.NewHook())
+func NewHook(args ...interface{}) *Hook {
+	return &Hook{args: args}
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook. Since logrus still logs an entry when a hook fails,
+// fields that can't be hushed are replaced by hush.HiddenValue before the error is
+// returned.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	data, err := hushData(entry.Context, entry.Data, h.args)
+	entry.Data = data
+	return err
+}
+
+// Formatter is a logrus.Formatter hushing the fields of every entry before formatting
+// it with the wrapped formatter.
+type Formatter struct {
+	next logrus.Formatter
+	args []interface{}
+}
+
+// NewFormatter returns a formatter hushing the fields of every entry before passing it
+// on to next. It accepts the same arguments as hush.Husher.Hush.
+//
+//	logger.SetFormatter(hushlogrus.NewFormatter(&logrus.JSONFormatter{}))
+func NewFormatter(next logrus.Formatter, args ...interface{}) *Formatter {
+	return &Formatter{next: next, args: args}
+}
+
+// Format implements logrus.Formatter.
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data, err := hushData(entry.Context, entry.Data, f.args)
+	if err != nil {
+		return nil, err
+	}
+	hushed := *entry
+	hushed.Data = data
+	return f.next.Format(&hushed)
+}
+
+// hushData returns data with every value hush.HasTags reports replaced by its hushed value,
+// or by hush.HiddenValue if it can't be hushed, along with the first error met.
+// data is left untouched.
+func hushData(ctx context.Context, data logrus.Fields, args []interface{}) (logrus.Fields, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var hushed logrus.Fields
+	var firstErr error
+	for key, v := range data {
+		if !hush.HasTags(v, args...) {
+			continue
+		}
+		if hushed == nil {
+			hushed = make(logrus.Fields, len(data))
+			for k, v := range data {
+				hushed[k] = v
+			}
+		}
+		node, err := hush.HushTree(ctx, v, args...)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			hushed[key] = hush.HiddenValue
+			continue
+		}
+		hushed[key] = nodeValue(node)
+	}
+	if hushed == nil {
+		return data, nil
+	}
+	return hushed, firstErr
+}
+
+// nodeValue returns the value of a leaf, or the children of an inner node as a map.
+func nodeValue(node *hush.Node) interface{} {
+	if node.Leaf {
+		return node.Value
+	}
+	m := make(map[string]interface{}, len(node.Children))
+	for _, child := range node.Children {
+		m[child.Name] = nodeValue(child)
+	}
+	return m
+}
@@ -0,0 +1,119 @@
+package hushlogrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tlmanz/hush"
+)
+
+type address struct {
+	Street string `hush:"mask"`
+	City   string
+}
+
+type user struct {
+	Name     string
+	Password string `hush:"hide"`
+	Address  address
+}
+
+type plain struct {
+	Name string
+}
+
+var testUser = user{Name: "John", Password: "hunter2", Address: address{Street: "1 Main Street", City: "Springfield"}}
+
+var hushedUser = map[string]interface{}{
+	"Name":     "John",
+	"Password": hush.HiddenValue,
+	"Address":  map[string]interface{}{"Street": "1***********t", "City": "Springfield"},
+}
+
+func TestHushing(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *logrus.Logger)
+		want  map[string]interface{}
+	}{
+		{
+			name:  "Hook",
+			setup: func(l *logrus.Logger) { l.AddHook(NewHook()) },
+			want:  map[string]interface{}{"owner": hushedUser, "user": hushedUser, "plain": map[string]interface{}{"Name": "Jane"}},
+		},
+		{
+			name:  "Formatter",
+			setup: func(l *logrus.Logger) { l.SetFormatter(NewFormatter(l.Formatter)) },
+			want:  map[string]interface{}{"owner": hushedUser, "user": hushedUser, "plain": map[string]interface{}{"Name": "Jane"}},
+		},
+		{
+			name:  "Prefix",
+			setup: func(l *logrus.Logger) { l.AddHook(NewHook("u")) },
+			want: map[string]interface{}{
+				"owner": map[string]interface{}{"u": hushedUser},
+				"user":  map[string]interface{}{"u": hushedUser},
+				"plain": map[string]interface{}{"Name": "Jane"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logrus.New()
+			logger.SetOutput(&buf)
+			logger.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+			tt.setup(logger)
+
+			entry := logger.WithField("owner", testUser)
+			entry.WithFields(logrus.Fields{"user": &testUser, "plain": plain{Name: "Jane"}}).Info("signed in")
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON log line %q: %v", buf.String(), err)
+			}
+			delete(got, "level")
+			delete(got, "msg")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logged fields = %v, want %v", got, tt.want)
+			}
+			if entry.Data["owner"] != testUser {
+				t.Errorf("entry field = %v, want the original value", entry.Data["owner"])
+			}
+		})
+	}
+}
+
+type account struct {
+	Name  string
+	Email string `hush:"hash"`
+}
+
+func TestHookFailsClosed(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+	logger.AddHook(NewHook())
+
+	// Hashing without a hash key fails.
+	logger.WithFields(logrus.Fields{"account": account{Name: "John", Email: "john@example.com"}, "plain": plain{Name: "Jane"}}).Info("signed in")
+
+	if strings.Contains(buf.String(), "john@example.com") {
+		t.Fatalf("log line %q leaks the unhushed value", buf.String())
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON log line %q: %v", buf.String(), err)
+	}
+	if got["account"] != hush.HiddenValue {
+		t.Errorf("account = %v, want %q", got["account"], hush.HiddenValue)
+	}
+	if want := map[string]interface{}{"Name": "Jane"}; !reflect.DeepEqual(got["plain"], want) {
+		t.Errorf("plain = %v, want %v", got["plain"], want)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/tlmanz/hush/hushzap
+
+go 1.21.7
+
+require (
+	github.com/tlmanz/hush v0.2.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/olekukonko/tablewriter v0.0.5 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+)
@@ -0,0 +1,112 @@
+// Package hushzap hushes values logged with go.uber.org/zap.
+//
+// Object and Field hush a single value. NewCore wraps a zapcore.Core so that every field
+// holding a struct with hush tags is hushed before it is written.
+package hushzap
+
+import (
+	"context"
+
+	"github.com/tlmanz/hush"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// object wraps a value so that it is hushed when it is logged.
+type object struct {
+	v    interface{}
+	args []interface{}
+}
+
+// Object returns a zapcore.ObjectMarshaler that hushes v when it is logged. It accepts
+// the same arguments as hush.Husher.Hush. Structs are logged as nested objects whose keys,
+// joined by the separator, match the field names returned by Hush. Values hushed into a
+// single row, such as strings, are logged under the key "value".
+func Object(v interface{}, args ...interface{}) zapcore.ObjectMarshaler {
+	return object{v: v, args: args}
+}
+
+// Field returns a field logging v under key once hushed, see Object.
+func Field(key string, v interface{}, args ...interface{}) zap.Field {
+	return zap.Object(key, Object(v, args...))
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	node, err := hush.HushTree(context.Background(), o.v, o.args...)
+	if err != nil {
+		return err
+	}
+	if node.Leaf {
+		enc.AddString("value", node.Value)
+		return nil
+	}
+	return addChildren(enc, node)
+}
+
+// addChildren adds the children of node to enc, nesting inner nodes as objects.
+func addChildren(enc zapcore.ObjectEncoder, node *hush.Node) error {
+	for _, child := range node.Children {
+		if child.Leaf {
+			enc.AddString(child.Name, child.Value)
+			continue
+		}
+		child := child
+		if err := enc.AddObject(child.Name, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			return addChildren(enc, child)
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// core is a zapcore.Core that hushes struct fields before passing them on.
+type core struct {
+	zapcore.Core
+	args []interface{}
+}
+
+// NewCore returns a zapcore.Core that hushes every field logged with zap.Any or
+// zap.Reflect that hush.HasTags reports, such as a struct with hush tags, before passing
+// the entry to next. It accepts the same arguments as hush.Husher.Hush.
+func NewCore(next zapcore.Core, args ...interface{}) zapcore.Core {
+	return &core{Core: next, args: args}
+}
+
+// With implements zapcore.Core.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(c.hushFields(fields)), args: c.args}
+}
+
+// Check implements zapcore.Core.
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.hushFields(fields))
+}
+
+// hushFields returns fields with every value hush.HasTags reports replaced by its hushed
+// object. fields is left untouched.
+func (c *core) hushFields(fields []zapcore.Field) []zapcore.Field {
+	var hushed []zapcore.Field
+	for i, f := range fields {
+		if f.Type != zapcore.ReflectType || !hush.HasTags(f.Interface, c.args...) {
+			continue
+		}
+		if hushed == nil {
+			hushed = append([]zapcore.Field(nil), fields...)
+		}
+		hushed[i] = Field(f.Key, f.Interface, c.args...)
+	}
+	if hushed == nil {
+		return fields
+	}
+	return hushed
+}
@@ -0,0 +1,82 @@
+package hushzap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tlmanz/hush"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type address struct {
+	Street string `hush:"mask"`
+	City   string
+}
+
+type user struct {
+	Name     string
+	Password string `hush:"hide"`
+	Address  address
+}
+
+type plain struct {
+	Name string
+}
+
+var testUser = user{Name: "John", Password: "hunter2", Address: address{Street: "1 Main Street", City: "Springfield"}}
+
+var hushedUser = map[string]interface{}{
+	"Name":     "John",
+	"Password": hush.HiddenValue,
+	"Address":  map[string]interface{}{"Street": "1***********t", "City": "Springfield"},
+}
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zap.Field
+		want  interface{}
+	}{
+		{"Struct", Field("user", testUser), hushedUser},
+		{"Pointer", Field("user", &testUser), hushedUser},
+		{"Prefix", Field("user", testUser.Address, "address"), map[string]interface{}{
+			"address": map[string]interface{}{"Street": "1***********t", "City": "Springfield"},
+		}},
+		{"String", Field("user", "hunter2", hush.TagHide), map[string]interface{}{"value": hush.HiddenValue}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			tt.field.AddTo(enc)
+			if got := enc.Fields["user"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Field() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCore(t *testing.T) {
+	obs, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(NewCore(obs)).With(zap.Any("owner", testUser))
+
+	logger.Debug("dropped", zap.Any("user", testUser))
+	logger.Info("signed in", zap.Any("user", &testUser), zap.Any("plain", plain{Name: "Jane"}), zap.String("id", "42"))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	got := entries[0].ContextMap()
+	want := map[string]interface{}{
+		"owner": hushedUser,
+		"user":  hushedUser,
+		"plain": plain{Name: "Jane"},
+		"id":    "42",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/tlmanz/hush/hushzerolog
+
+go 1.21.7
+
+require (
+	github.com/rs/zerolog v1.33.0
+	github.com/tlmanz/hush v0.2.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/olekukonko/tablewriter v0.0.5 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
@@ -0,0 +1,78 @@
+// Package hushzerolog hushes values logged with github.com/rs/zerolog.
+//
+// Object hushes a single value. MarshalFunc wraps zerolog.InterfaceMarshalFunc so that
+// every struct with hush tags logged with Event.Interface or Context.Interface is hushed.
+package hushzerolog
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/tlmanz/hush"
+)
+
+// object wraps a value so that it is hushed when it is logged.
+type object struct {
+	v    interface{}
+	args []interface{}
+}
+
+// Object returns a zerolog.LogObjectMarshaler that hushes v when it is logged. It accepts
+// the same arguments as hush.Husher.Hush. Structs are logged as nested objects whose keys,
+// joined by the separator, match the field names returned by Hush. Values hushed into a
+// single row, such as strings, are logged under the key "value".
+//
+//	logger.Info().Object("user", hushzerolog.Object(user)).Msg("signed in")
+func Object(v interface{}, args ...interface{}) zerolog.LogObjectMarshaler {
+	return object{v: v, args: args}
+}
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler.
+func (o object) MarshalZerologObject(e *zerolog.Event) {
+	node, err := hush.HushTree(context.Background(), o.v, o.args...)
+	if err != nil {
+		e.AnErr("error", err)
+		return
+	}
+	if node.Leaf {
+		e.Str("value", node.Value)
+		return
+	}
+	addChildren(e, node)
+}
+
+// addChildren adds the children of node to e, nesting inner nodes as dictionaries.
+func addChildren(e *zerolog.Event, node *hush.Node) {
+	for _, child := range node.Children {
+		if child.Leaf {
+			e.Str(child.Name, child.Value)
+			continue
+		}
+		dict := zerolog.Dict()
+		addChildren(dict, child)
+		e.Dict(child.Name, dict)
+	}
+}
+
+// MarshalFunc returns a function for zerolog.InterfaceMarshalFunc that hushes every value
+// hush.HasTags reports, such as a struct with hush tags, and passes any other value on
+// to next.
+// It accepts the same arguments as hush.Husher.Hush.
+//
+//	zerolog.InterfaceMarshalFunc = hushzerolog.MarshalFunc(zerolog.InterfaceMarshalFunc)
+func MarshalFunc(next func(v interface{}) ([]byte, error), args ...interface{}) func(v interface{}) ([]byte, error) {
+	args = append(args[:len(args):len(args)], hush.WithRenderer(hush.NestedJSONRenderer{}))
+
+	return func(v interface{}) ([]byte, error) {
+		if !hush.HasTags(v, args...) {
+			return next(v)
+		}
+
+		var buf bytes.Buffer
+		if err := hush.HushTo(context.Background(), &buf, v, args...); err != nil {
+			return nil, err
+		}
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+}
@@ -0,0 +1,90 @@
+package hushzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/tlmanz/hush"
+)
+
+type address struct {
+	Street string `hush:"mask"`
+	City   string
+}
+
+type user struct {
+	Name     string
+	Password string `hush:"hide"`
+	Address  address
+}
+
+type plain struct {
+	Name string
+}
+
+var testUser = user{Name: "John", Password: "hunter2", Address: address{Street: "1 Main Street", City: "Springfield"}}
+
+var hushedUser = map[string]interface{}{
+	"Name":     "John",
+	"Password": hush.HiddenValue,
+	"Address":  map[string]interface{}{"Street": "1***********t", "City": "Springfield"},
+}
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON log line %q: %v", buf.String(), err)
+	}
+	delete(got, "level")
+	delete(got, "message")
+	return got
+}
+
+func TestObject(t *testing.T) {
+	tests := []struct {
+		name  string
+		value zerolog.LogObjectMarshaler
+		want  interface{}
+	}{
+		{"Struct", Object(testUser), hushedUser},
+		{"Pointer", Object(&testUser), hushedUser},
+		{"Prefix", Object(testUser.Address, "address"), map[string]interface{}{
+			"address": map[string]interface{}{"Street": "1***********t", "City": "Springfield"},
+		}},
+		{"String", Object("hunter2", hush.TagHide), map[string]interface{}{"value": hush.HiddenValue}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			logger.Info().Object("user", tt.value).Msg("signed in")
+			if got := decodeLine(t, &buf)["user"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Object() logged %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalFunc(t *testing.T) {
+	defer func(f func(v interface{}) ([]byte, error)) { zerolog.InterfaceMarshalFunc = f }(zerolog.InterfaceMarshalFunc)
+	zerolog.InterfaceMarshalFunc = MarshalFunc(zerolog.InterfaceMarshalFunc)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).With().Interface("owner", testUser).Logger()
+	logger.Info().Interface("user", &testUser).Interface("plain", plain{Name: "Jane"}).Msg("signed in")
+
+	got := decodeLine(t, &buf)
+	want := map[string]interface{}{
+		"owner": hushedUser,
+		"user":  hushedUser,
+		"plain": map[string]interface{}{"Name": "Jane"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logged fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package hush
+
+import (
+	"context"
+	"errors"
+)
+
+// errStopIteration is returned through the traversal once the yield function of
+// HushIter asks to stop.
+var errStopIteration = errors.New("hush: iteration stopped")
+
+// HushIter hushes v like Husher.Hush, but calls yield with the path and the hushed value
+// of every row as the traversal produces them, so large values don't have to be held in
+// memory as a whole. Iteration stops early, without error, when yield returns false.
+//
+// The elements of slices and arrays are passed on one at a time. Rows of structs and
+// maps are sorted, and therefore collected, unless WithOrder sets OrderDeclaration or
+// OrderNone. Rows are produced on a single goroutine, in the order Hush would return
+// them.
+func HushIter(ctx context.Context, v interface{}, yield func(path, value string) bool, args ...interface{}) error {
+	opts := newHushOptions(args)
+	opts.emit = func(f Field) bool {
+		return yield(f.Path, f.Value)
+	}
+
+	ht := &hushType{}
+	fields, err := ht.hush(ctx, v, opts)
+	if err == nil {
+		err = emitFields(opts, fields)
+	}
+	if errors.Is(err, errStopIteration) {
+		return nil
+	}
+	return err
+}
+
+// streamEach calls fn for every index in [0, n) in order and passes its fields on to opts.emit.
+func streamEach(ctx context.Context, opts *hushOptions, n int, fn func(i int) ([]Field, error)) error {
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		fields, err := fn(i)
+		if err != nil {
+			return err
+		}
+		if err := emitFields(opts, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitFields passes fields on to opts.emit, returning errStopIteration once it asks to stop.
+func emitFields(opts *hushOptions, fields []Field) error {
+	for _, f := range fields {
+		if !opts.emit(f) {
+			return errStopIteration
+		}
+	}
+	return nil
+}
+
+// collecting returns options that collect rows instead of streaming them, for values
+// whose rows must all be known before they are passed on.
+func (o *hushOptions) collecting() *hushOptions {
+	if o.emit == nil {
+		return o
+	}
+	collecting := *o
+	collecting.emit = nil
+	return &collecting
+}
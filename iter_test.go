@@ -0,0 +1,126 @@
+package hush
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type iterRecord struct {
+	Name  string `hush:"mask"`
+	Email string `hush:"hide"`
+	ID    int
+}
+
+func TestHushIter(t *testing.T) {
+	records := []iterRecord{
+		{Name: "alice", Email: "alice@example.com", ID: 1},
+		{Name: "bob", Email: "bob@example.com", ID: 2},
+	}
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		options []interface{}
+		limit   int
+		want    [][]string
+	}{
+		{
+			name:    "Slice of structs",
+			input:   records,
+			options: []interface{}{"records"},
+			want: [][]string{
+				{"records[0].Email", "HIDDEN"},
+				{"records[0].ID", "1"},
+				{"records[0].Name", "*****"},
+				{"records[1].Email", "HIDDEN"},
+				{"records[1].ID", "2"},
+				{"records[1].Name", "***"},
+			},
+		},
+		{
+			name:    "Unsorted struct",
+			input:   records[0],
+			options: []interface{}{WithSorting(false)},
+			want: [][]string{
+				{"Name", "*****"},
+				{"Email", "HIDDEN"},
+				{"ID", "1"},
+			},
+		},
+		{
+			name:  "Stop early",
+			input: records,
+			limit: 2,
+			want: [][]string{
+				{"[0].Email", "HIDDEN"},
+				{"[0].ID", "1"},
+			},
+		},
+		{
+			// The invalid rule of the last element is only reached if rows are collected first.
+			name:    "Stop early in a slice of strings",
+			input:   []string{"a", "b", "c"},
+			options: []interface{}{TagMask, Rule{Path: "[2]", Type: HushType("unknown")}},
+			limit:   2,
+			want:    [][]string{{"[0]", "*"}, {"[1]", "*"}},
+		},
+		{
+			name:    "String",
+			input:   "secret",
+			options: []interface{}{TagHide},
+			want:    [][]string{{"", "HIDDEN"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			err := HushIter(context.Background(), tt.input, func(path, value string) bool {
+				got = append(got, []string{path, value})
+				return tt.limit == 0 || len(got) < tt.limit
+			}, tt.options...)
+			if err != nil {
+				t.Fatalf("HushIter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HushIter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHushIterMatchesHush(t *testing.T) {
+	input := newBenchDeep()
+
+	want, err := NewHush().Hush(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+
+	var got [][]string
+	err = HushIter(context.Background(), input, func(path, value string) bool {
+		got = append(got, []string{path, value})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("HushIter() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HushIter() returned %d rows differing from the %d rows of Hush()", len(got), len(want))
+	}
+}
+
+func TestHushIterCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := HushIter(ctx, []iterRecord{{Name: "alice"}}, func(path, value string) bool {
+		t.Errorf("yield called with %s=%s after cancellation", path, value)
+		return true
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("HushIter() error = %v, want %v", err, context.Canceled)
+	}
+}
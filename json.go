@@ -0,0 +1,194 @@
+package hush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// HushJSON hushes a raw JSON document according to rules and returns the resulting JSON.
+// It accepts the same arguments as Husher.Hush; objects are addressed like structs and
+// maps, so a key can be matched by either "headers.Authorization" or "headers[Authorization]",
+// and array elements like slices, e.g. "users[0]". See Rule for the matching rules.
+// A rule applies to the matched value and everything below it. Removed values are
+// dropped from their object or array, masked and hidden values become JSON strings.
+// Key order and the formatting of numbers that are not hushed are preserved.
+func HushJSON(ctx context.Context, data []byte, args ...interface{}) ([]byte, error) {
+	opts := newHushOptions(args)
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	jh := &jsonHusher{
+		ctx:  ctx,
+		dec:  dec,
+		opts: opts,
+	}
+
+	if err := jh.value(opts.prefix, ""); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("hush: unexpected data after top-level JSON value")
+		}
+		return nil, err
+	}
+
+	return jh.buf.Bytes(), nil
+}
+
+// jsonHusher re-encodes a JSON token stream while applying the rules.
+type jsonHusher struct {
+	ctx  context.Context
+	dec  *json.Decoder
+	opts *hushOptions
+	buf  bytes.Buffer
+}
+
+// value reads the next JSON value from the stream and writes its hushed form.
+// hushTag is the hush type inherited from the closest matching rule above it.
+func (jh *jsonHusher) value(path string, hushTag string) error {
+	tok, err := jh.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return jh.object(path, hushTag)
+		}
+		return jh.array(path, hushTag)
+	case string:
+		jh.leaf(t, true, hushTag)
+	case json.Number:
+		jh.leaf(t.String(), false, hushTag)
+	case bool:
+		jh.leaf(strconv.FormatBool(t), false, hushTag)
+	default:
+		jh.buf.WriteString("null")
+	}
+	return nil
+}
+
+// object writes the members of an object whose opening delimiter was already read.
+func (jh *jsonHusher) object(path string, hushTag string) error {
+	jh.buf.WriteByte('{')
+	first := true
+	for jh.dec.More() {
+		select {
+		case <-jh.ctx.Done():
+			return jh.ctx.Err()
+		default:
+		}
+
+		tok, err := jh.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		memberPath := buildFieldName(path, escapeFieldName(key, jh.opts.separator), jh.opts.separator)
+		memberTag, err := jh.match(memberPath, hushTag)
+		if err != nil {
+			return err
+		}
+		if tagAction(memberTag) == TagRemove {
+			if err := jh.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !first {
+			jh.buf.WriteByte(',')
+		}
+		first = false
+		writeJSONString(&jh.buf, key)
+		jh.buf.WriteByte(':')
+		if err := jh.value(memberPath, memberTag); err != nil {
+			return err
+		}
+	}
+	jh.buf.WriteByte('}')
+
+	_, err := jh.dec.Token() // closing '}'
+	return err
+}
+
+// array writes the elements of an array whose opening delimiter was already read.
+func (jh *jsonHusher) array(path string, hushTag string) error {
+	jh.buf.WriteByte('[')
+	first := true
+	for i := 0; jh.dec.More(); i++ {
+		select {
+		case <-jh.ctx.Done():
+			return jh.ctx.Err()
+		default:
+		}
+
+		elemPath := path + "[" + strconv.Itoa(i) + "]"
+		elemTag, err := jh.match(elemPath, hushTag)
+		if err != nil {
+			return err
+		}
+		if tagAction(elemTag) == TagRemove {
+			if err := jh.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !first {
+			jh.buf.WriteByte(',')
+		}
+		first = false
+		if err := jh.value(elemPath, elemTag); err != nil {
+			return err
+		}
+	}
+	jh.buf.WriteByte(']')
+
+	_, err := jh.dec.Token() // closing ']'
+	return err
+}
+
+// leaf writes a scalar value. Numbers and booleans that are not hushed keep their
+// original encoding, hushed values are always written as strings.
+func (jh *jsonHusher) leaf(value string, isString bool, hushTag string) {
+	hushed := hushString(value, hushTag, jh.opts)
+	if hushed == value && !isString {
+		jh.buf.WriteString(value)
+		return
+	}
+	writeJSONString(&jh.buf, hushed)
+}
+
+// skip reads and discards the next JSON value.
+func (jh *jsonHusher) skip() error {
+	var discard json.RawMessage
+	return jh.dec.Decode(&discard)
+}
+
+// match returns the hush type of the rule matching path, or inherited if none does.
+func (jh *jsonHusher) match(path string, inherited string) (string, error) {
+	if hushType := jh.opts.matchRule(path); hushType != "" {
+		return hushType, jh.opts.checkHushTag(path, hushType)
+	}
+	return inherited, nil
+}
+
+// writeJSONString writes s as a JSON string without escaping HTML characters.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // drop the newline added by Encode
+}
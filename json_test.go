@@ -0,0 +1,83 @@
+package hush
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHushJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		args    []interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "No rules",
+			input: `{"b": 1.50, "a": [1e3, true, null], "c": "<x>"}`,
+			want:  `{"b":1.50,"a":[1e3,true,null],"c":"<x>"}`,
+		},
+		{
+			name:  "Dotted and indexed paths",
+			input: `{"users": [{"name": "John", "password": "secret123"}, {"name": "Alice", "password": "hunter22"}]}`,
+			args:  []interface{}{Rule{Path: "users[0].password", Type: TagHide}, Rule{Path: "users[1].name", Type: TagMask}},
+			want:  `{"users":[{"name":"John","password":"HIDDEN"},{"name":"*****","password":"hunter22"}]}`,
+		},
+		{
+			name:  "Map style path",
+			input: `{"headers": {"Authorization": "Bearer abc", "Accept": "*/*"}}`,
+			args:  []interface{}{Rule{Path: "headers[Authorization]", Type: TagRemove}},
+			want:  `{"headers":{"Accept":"*/*"}}`,
+		},
+		{
+			name:  "Rule applies to subtree",
+			input: `{"card": {"number": 4111111111111111, "cvv": "123"}, "id": 7}`,
+			args:  []interface{}{[]Rule{{Path: "card", Type: TagMask}}},
+			want:  `{"card":{"number":"4**************1","cvv":"***"},"id":7}`,
+		},
+		{
+			name:  "Remove array element",
+			input: `[1, 2, 3]`,
+			args:  []interface{}{Rule{Path: "[1]", Type: TagRemove}},
+			want:  `[1,3]`,
+		},
+		{
+			name:  "Custom separator",
+			input: `{"a": {"b": "value"}}`,
+			args:  []interface{}{WithSeparator("_"), Rule{Path: "a_b", Type: TagHide}},
+			want:  `{"a":{"b":"HIDDEN"}}`,
+		},
+		{
+			name:    "Invalid JSON",
+			input:   `{"a": }`,
+			wantErr: true,
+		},
+		{
+			name:    "Trailing data",
+			input:   `{} {}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HushJSON(context.Background(), []byte(tt.input), tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HushJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("HushJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHushJSONCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := HushJSON(ctx, []byte(`{"a": 1}`)); err != context.Canceled {
+		t.Errorf("HushJSON() error = %v, want %v", err, context.Canceled)
+	}
+}
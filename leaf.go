@@ -0,0 +1,154 @@
+package hush
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Redactor is implemented by types that know how to present themselves when hushed.
+// HushValue returns the value to use in place of the receiver and, optionally, the hush
+// type to apply to it when the field has no hush tag or rule of its own.
+type Redactor interface {
+	HushValue(ctx context.Context) (string, HushType)
+}
+
+var (
+	redactorType      = reflect.TypeOf((*Redactor)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// receiver tells whether a type implements an interface, and through which receiver.
+type receiver int
+
+const (
+	receiverNone receiver = iota
+	receiverValue
+	receiverPointer
+)
+
+// leafMethods records which of the leaf interfaces a type implements.
+type leafMethods struct {
+	redactor      receiver
+	textMarshaler receiver
+	stringer      receiver
+	// descended is set for types hush descends into: structs with exported fields,
+	// slices, arrays and maps. Their TextMarshaler and Stringer methods are ignored so
+	// the hush tags of their contents keep applying.
+	descended bool
+}
+
+// leafMethodsCache caches the leafMethods of every type, keyed by reflect.Type.
+var leafMethodsCache sync.Map
+
+// methodsFor returns the leafMethods of t, computing and caching them on first use.
+func methodsFor(t reflect.Type) *leafMethods {
+	if cached, ok := leafMethodsCache.Load(t); ok {
+		return cached.(*leafMethods)
+	}
+
+	m := &leafMethods{
+		redactor:      implements(t, redactorType),
+		textMarshaler: implements(t, textMarshalerType),
+		stringer:      implements(t, stringerType),
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		m.descended = true
+	case reflect.Struct:
+		m.descended = planFor(t).exported > 0
+	}
+
+	cached, _ := leafMethodsCache.LoadOrStore(t, m)
+	return cached.(*leafMethods)
+}
+
+// implements reports whether t or *t implements iface.
+func implements(t, iface reflect.Type) receiver {
+	switch {
+	case t.Implements(iface):
+		return receiverValue
+	case reflect.PointerTo(t).Implements(iface):
+		return receiverPointer
+	default:
+		return receiverNone
+	}
+}
+
+// hasLeafMethods reports whether values of type t may present themselves as a single
+// value through a Redactor, a type handler, an encoding.TextMarshaler or a fmt.Stringer.
+func hasLeafMethods(t reflect.Type) bool {
+	if hasTypeHandler(t) {
+		return true
+	}
+	m := methodsFor(t)
+	if m.redactor != receiverNone {
+		return true
+	}
+	return !m.descended && (m.textMarshaler != receiverNone || m.stringer != receiverNone)
+}
+
+// resolveLeaf returns the string hushed in place of v when v presents itself as a single
+// value. It checks, in order, for a Redactor, a handler registered with RegisterTypeHandler,
+// an encoding.TextMarshaler and a fmt.Stringer. The returned hush type is the one suggested
+// by a Redactor, if any. Pointers and interfaces are never leaves themselves; the values
+// they point to are checked once they are dereferenced.
+func resolveLeaf(ctx context.Context, v reflect.Value) (string, HushType, bool) {
+	if !v.IsValid() || v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return "", "", false
+	}
+
+	m := methodsFor(v.Type())
+
+	if r, ok := methodReceiver(v, m.redactor).(Redactor); ok {
+		value, hushType := r.HushValue(ctx)
+		return value, hushType, true
+	}
+
+	if handler, hv, ok := lookupTypeHandler(v); ok {
+		return handler(hv), "", true
+	}
+
+	if m.descended {
+		return "", "", false
+	}
+
+	if tm, ok := methodReceiver(v, m.textMarshaler).(encoding.TextMarshaler); ok {
+		if text, err := tm.MarshalText(); err == nil {
+			return string(text), "", true
+		}
+	}
+
+	if s, ok := methodReceiver(v, m.stringer).(fmt.Stringer); ok {
+		return s.String(), "", true
+	}
+
+	return "", "", false
+}
+
+// methodReceiver returns v, or a pointer to it, as an interface value on which the methods
+// of the given receiver kind can be called. It returns nil when there is no such receiver,
+// such as for values of unexported fields that aren't addressable.
+func methodReceiver(v reflect.Value, r receiver) interface{} {
+	if r == receiverNone {
+		return nil
+	}
+
+	if !v.CanInterface() {
+		if !v.CanAddr() {
+			return nil
+		}
+		v = exposeField(v)
+	}
+
+	if r == receiverPointer {
+		if !v.CanAddr() {
+			return nil
+		}
+		v = v.Addr()
+	}
+	return v.Interface()
+}
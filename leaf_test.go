@@ -0,0 +1,94 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type cardNumber string
+
+func (c cardNumber) HushValue(ctx context.Context) (string, HushType) {
+	return string(c), TagMask
+}
+
+type secretToken struct {
+	value string
+}
+
+func (s *secretToken) HushValue(ctx context.Context) (string, HushType) {
+	return s.value, TagRemove
+}
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("!", int(l))), nil
+}
+
+func (l level) String() string {
+	return "unused"
+}
+
+type opaque struct {
+	id int
+}
+
+func (o opaque) String() string {
+	return "opaque"
+}
+
+type labelled struct {
+	Name string `hush:"hide"`
+}
+
+func (l labelled) String() string {
+	return "labelled " + l.Name
+}
+
+func TestResolveLeaf(t *testing.T) {
+	type record struct {
+		Card     cardNumber
+		Shown    cardNumber `hush:"hide,value=-"`
+		Token    secretToken
+		Level    level
+		Opaque   opaque
+		Labelled labelled
+		Cards    []cardNumber
+	}
+
+	input := record{
+		Card:     "4111",
+		Shown:    "4111",
+		Token:    secretToken{value: "abc"},
+		Level:    3,
+		Opaque:   opaque{id: 1},
+		Labelled: labelled{Name: "secret"},
+		Cards:    []cardNumber{"12"},
+	}
+
+	got, err := NewHush().Hush(context.Background(), &input)
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{
+		{"Card", "****"},
+		{"Cards[0]", "**"},
+		{"Labelled.Name", HiddenValue},
+		{"Level", "!!!"},
+		{"Opaque", "opaque"},
+		{"Shown", "-"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hush() = %v, want %v", got, want)
+	}
+
+	sanitized, err := Sanitize(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if sanitized.Card != "****" || sanitized.Token.value != "" || sanitized.Level != 3 || sanitized.Labelled.Name != HiddenValue {
+		t.Errorf("Sanitize() = %+v, want Card masked, Token zeroed, Level kept and Labelled.Name hidden", sanitized)
+	}
+}
@@ -1,5 +1,7 @@
 package hush
 
+import "sort"
+
 // Option is a function type for configuring hushOptions.
 type Option func(*hushOptions)
 
@@ -10,6 +12,38 @@ type hushOptions struct {
 	includePrivate bool
 	prefix         string
 	hushType       HushType
+	rules          []Rule
+	ruleSet        *ruleSet
+	renderer       Renderer
+	detectors      []Detector
+	hashKey        []byte
+	hashPrefix     string
+	keyProvider    KeyProvider
+	concurrency    int
+	limiter        chan struct{}
+	maxDepth       int
+	order          Order
+	envNames       bool
+
+	// currentKey caches the current key for the call, see currentCipher.
+	currentKey *currentKey
+
+	// emit receives the fields of HushIter as they are produced, see streamEach.
+	emit func(f Field) bool
+}
+
+// depthLimit returns the maximum depth set with WithMaxDepth, or the default one.
+func (o *hushOptions) depthLimit() int {
+	if o.maxDepth < 1 {
+		return maxRecursionDepth
+	}
+	return o.maxDepth
+}
+
+// validate reports whether the hush type passed as an argument is a valid hush tag
+// that can be applied with the options.
+func (o *hushOptions) validate() error {
+	return o.checkHushTag("", string(o.hushType))
 }
 
 // WithSeparator sets the separator used for nested field names.
@@ -33,6 +67,105 @@ func WithPrivateFields(include bool) Option {
 	}
 }
 
+// WithFieldRules adds a rule for every path pattern in the map. See Rule for the
+// pattern syntax; rules from the map are added in the order of their patterns.
+func WithFieldRules(rules map[string]HushType) Option {
+	return func(o *hushOptions) {
+		patterns := make([]string, 0, len(rules))
+		for pattern := range rules {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+
+		for _, pattern := range patterns {
+			o.rules = append(o.rules, Rule{Path: pattern, Type: rules[pattern]})
+		}
+	}
+}
+
+// WithRenderer sets the renderer used by HushTo.
+func WithRenderer(r Renderer) Option {
+	return func(o *hushOptions) {
+		o.renderer = r
+	}
+}
+
+// WithDetectors adds detectors that mask the values of fields without a hush tag
+// when they recognise them, e.g. WithDetectors(DefaultDetectors()...).
+func WithDetectors(detectors ...Detector) Option {
+	return func(o *hushOptions) {
+		o.detectors = append(o.detectors, detectors...)
+	}
+}
+
+// WithHashKey sets the HMAC key used to tokenize values tagged with hush:"hash".
+func WithHashKey(key []byte) Option {
+	return func(o *hushOptions) {
+		o.hashKey = key
+	}
+}
+
+// WithHashPrefix sets the prefix of the tokens produced for values tagged with hush:"hash".
+func WithHashPrefix(prefix string) Option {
+	return func(o *hushOptions) {
+		o.hashPrefix = prefix
+	}
+}
+
+// WithKeyProvider sets the key provider used to seal values tagged with hush:"encrypt".
+func WithKeyProvider(kp KeyProvider) Option {
+	return func(o *hushOptions) {
+		o.keyProvider = kp
+	}
+}
+
+// WithConcurrency sets how many goroutines may process a single value: ConcurrencyAuto
+// (the default) only fans out for large values, ConcurrencySequential never does, and
+// any larger n always fans out using at most n goroutines.
+func WithConcurrency(n int) Option {
+	return func(o *hushOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithMaxDepth sets how deeply nested values are descended into. Values nested deeper
+// are rendered as "[max depth exceeded]". A limit below 1 restores the default of 64.
+func WithMaxDepth(n int) Option {
+	return func(o *hushOptions) {
+		o.maxDepth = n
+	}
+}
+
+// WithSorting sets whether rows are sorted by path, which is the default. It is a shorthand
+// for WithOrder(OrderAlphabetical) and WithOrder(OrderNone).
+func WithSorting(sorted bool) Option {
+	if sorted {
+		return WithOrder(OrderAlphabetical)
+	}
+	return WithOrder(OrderNone)
+}
+
+// WithOrder sets the order of the rows of structs and maps. OrderAlphabetical, the default,
+// and OrderNatural sort them by path once they are all known, while OrderDeclaration and
+// OrderNone keep struct fields in declaration order, which lets HushIter pass rows on
+// without holding whole structs and maps in memory.
+func WithOrder(order Order) Option {
+	return func(o *hushOptions) {
+		o.order = order
+	}
+}
+
+// WithEnvNames sets whether struct fields with an env or envconfig tag are named after
+// the environment variable they are populated from, e.g. "DB_PASSWORD" for a field tagged
+// env:"DB_PASSWORD", instead of their path. Prefixes set with envPrefix tags on enclosing
+// struct fields are prepended. Rows named this way are matched against the rules by that
+// name, so DefaultEnvRules apply to them as well.
+func WithEnvNames(enabled bool) Option {
+	return func(o *hushOptions) {
+		o.envNames = enabled
+	}
+}
+
 // WithOptions sets all options at once
 func WithOptions(options *hushOptions) Option {
 	return func(o *hushOptions) {
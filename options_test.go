@@ -22,4 +22,14 @@ func TestOptions(t *testing.T) {
 	if !opts.includePrivate {
 		t.Errorf("WithPrivateFields(true) failed, got: false, want: true")
 	}
+
+	WithHashKey([]byte("key"))(opts)
+	if string(opts.hashKey) != "key" {
+		t.Errorf("WithHashKey() failed, got: %s, want: key", opts.hashKey)
+	}
+
+	WithHashPrefix("id_")(opts)
+	if opts.hashPrefix != "id_" {
+		t.Errorf("WithHashPrefix() failed, got: %s, want: id_", opts.hashPrefix)
+	}
 }
@@ -0,0 +1,114 @@
+package hush
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Order sets the order of the rows returned for structs and maps, see WithOrder.
+type Order int
+
+// Orders accepted by WithOrder.
+const (
+	// OrderAlphabetical sorts rows by path, byte by byte, so "Ages[10]" comes before
+	// "Ages[2]". This is the default.
+	OrderAlphabetical Order = iota
+
+	// OrderDeclaration keeps struct fields in the order they are declared in and sorts
+	// map entries by key, comparing numbers within keys by value.
+	OrderDeclaration
+
+	// OrderNatural sorts rows by path, comparing numbers within paths by value, so
+	// "Ages[2]" comes before "Ages[10]".
+	OrderNatural
+
+	// OrderNone keeps struct fields in declaration order and map entries in no
+	// particular order.
+	OrderNone
+)
+
+// sortsRows reports whether the rows of a struct or map are sorted once they are all known.
+func (o Order) sortsRows() bool {
+	return o == OrderAlphabetical || o == OrderNatural
+}
+
+// sortFields sorts fields by path in the given order.
+func sortFields(fields []Field, order Order) {
+	less := func(a, b string) bool { return a < b }
+	if order == OrderNatural {
+		less = naturalLess
+	}
+	sort.Sort(fieldsByPath{fields: fields, less: less})
+}
+
+// fieldsByPath sorts fields by path. Unlike sort.Slice, it swaps fields without going
+// through reflection, which is costly for values as large as Field.
+type fieldsByPath struct {
+	fields []Field
+	less   func(a, b string) bool
+}
+
+func (f fieldsByPath) Len() int           { return len(f.fields) }
+func (f fieldsByPath) Less(i, j int) bool { return f.less(f.fields[i].Path, f.fields[j].Path) }
+func (f fieldsByPath) Swap(i, j int)      { f.fields[i], f.fields[j] = f.fields[j], f.fields[i] }
+
+// naturalLess reports whether a sorts before b, comparing runs of digits by their value.
+// Runs of equal value are ordered by their number of leading zeros.
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if !isDigit(a[i]) || !isDigit(b[j]) {
+			if a[i] != b[j] {
+				return a[i] < b[j]
+			}
+			i++
+			j++
+			continue
+		}
+
+		startA, startB := i, j
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+
+		numA, numB := trimZeros(a[startA:i]), trimZeros(b[startB:j])
+		if len(numA) != len(numB) {
+			return len(numA) < len(numB)
+		}
+		if numA != numB {
+			return numA < numB
+		}
+		if i-startA != j-startB {
+			return i-startA < j-startB
+		}
+	}
+	return len(a)-i < len(b)-j
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// trimZeros trims the leading zeros of a run of digits.
+func trimZeros(digits string) string {
+	for len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	return digits
+}
+
+// keysByName sorts map keys by their formatted names, comparing numbers by value.
+type keysByName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (k keysByName) Len() int           { return len(k.keys) }
+func (k keysByName) Less(i, j int) bool { return naturalLess(k.names[i], k.names[j]) }
+func (k keysByName) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.names[i], k.names[j] = k.names[j], k.names[i]
+}
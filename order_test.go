@@ -0,0 +1,131 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWithOrder(t *testing.T) {
+	type person struct {
+		Name   string `hush:"mask"`
+		Ages   []int
+		Scores map[string]int
+	}
+
+	input := person{
+		Name:   "John",
+		Ages:   []int{8, 9, 10},
+		Scores: map[string]int{"q10": 1, "q2": 2},
+	}
+
+	tests := []struct {
+		name    string
+		options []interface{}
+		want    [][]string
+	}{
+		{
+			name:    "Alphabetical",
+			options: []interface{}{WithOrder(OrderAlphabetical)},
+			want: [][]string{
+				{"Ages[0]", "8"}, {"Ages[1]", "9"}, {"Ages[2]", "10"},
+				{"Name", "****"},
+				{"Scores[q10]", "1"}, {"Scores[q2]", "2"},
+			},
+		},
+		{
+			name:    "Declaration",
+			options: []interface{}{WithOrder(OrderDeclaration)},
+			want: [][]string{
+				{"Name", "****"},
+				{"Ages[0]", "8"}, {"Ages[1]", "9"}, {"Ages[2]", "10"},
+				{"Scores[q2]", "2"}, {"Scores[q10]", "1"},
+			},
+		},
+		{
+			name:    "Declaration with concurrency",
+			options: []interface{}{WithOrder(OrderDeclaration), WithConcurrency(8)},
+			want: [][]string{
+				{"Name", "****"},
+				{"Ages[0]", "8"}, {"Ages[1]", "9"}, {"Ages[2]", "10"},
+				{"Scores[q2]", "2"}, {"Scores[q10]", "1"},
+			},
+		},
+		{
+			name:    "Natural",
+			options: []interface{}{WithOrder(OrderNatural)},
+			want: [][]string{
+				{"Ages[0]", "8"}, {"Ages[1]", "9"}, {"Ages[2]", "10"},
+				{"Name", "****"},
+				{"Scores[q2]", "2"}, {"Scores[q10]", "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewHush().Hush(context.Background(), input, tt.options...)
+			if err != nil {
+				t.Fatalf("Hush() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hush() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOrderNatural(t *testing.T) {
+	input := make([]int, 12)
+	got, err := NewHush().Hush(context.Background(), struct{ Ages []int }{input}, WithOrder(OrderNatural))
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	if got[2][0] != "Ages[2]" || got[11][0] != "Ages[11]" {
+		t.Errorf("Hush() = %v, want indexes in numeric order", got)
+	}
+}
+
+func TestWithOrderNone(t *testing.T) {
+	input := struct {
+		B string
+		A map[string]int
+	}{B: "b", A: map[string]int{"x": 1, "y": 2}}
+
+	got, err := NewHush().Hush(context.Background(), input, WithOrder(OrderNone))
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	if len(got) != 3 || got[0][0] != "B" {
+		t.Fatalf("Hush() = %v, want B first followed by the entries of A", got)
+	}
+	entries := []string{got[1][0], got[2][0]}
+	sort.Strings(entries)
+	if !reflect.DeepEqual(entries, []string{"A[x]", "A[y]"}) {
+		t.Errorf("Hush() entries = %v, want A[x] and A[y]", entries)
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Ages[2]", "Ages[10]", true},
+		{"Ages[10]", "Ages[2]", false},
+		{"a", "b", true},
+		{"a", "a", false},
+		{"a", "a1", true},
+		{"x1y", "x01y", true},
+		{"x01y", "x1y", false},
+		{"item9.b", "item10.a", true},
+		{"9", "a", true},
+	}
+
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package hush
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SegmentKind is the kind of a path segment.
+type SegmentKind int
+
+// Kinds of path segments.
+const (
+	// SegmentField is a struct field or a JSON object member, written as its name.
+	SegmentField SegmentKind = iota
+	// SegmentIndex is an element of a slice or array, written as "[N]".
+	SegmentIndex
+	// SegmentKey is a map entry, written as "[key]".
+	SegmentKey
+)
+
+// Segment is a single step of a Path.
+type Segment struct {
+	Kind SegmentKind
+	// Name is the name of the field, or the map key formatted with %v.
+	Name string
+	// Index is the index of the element.
+	Index int
+}
+
+// Path is the path of a field, as a list of typed segments.
+//
+// Formatted paths are the field names returned by Hush: fields are joined by the
+// separator, and indexes and map keys follow in brackets, as in "users[0].tags[admin]".
+// Backslashes escape the characters that would otherwise be read as syntax: "\", "["
+// and "]" in map keys, along with the separator in field names. Map keys made of
+// digits only, such as those of a map[int]T, look like indexes: ParsePath reads them as
+// indexes, while ParsePathOf tells them apart using the type of the hushed value.
+type Path []Segment
+
+// Format returns the path as a field name, with fields joined by separator.
+func (p Path) Format(separator string) string {
+	var b strings.Builder
+	for i, s := range p {
+		switch s.Kind {
+		case SegmentIndex:
+			b.WriteString("[" + strconv.Itoa(s.Index) + "]")
+		case SegmentKey:
+			b.WriteString("[" + escapeKey(s.Name) + "]")
+		default:
+			if i > 0 {
+				b.WriteString(separator)
+			}
+			b.WriteString(escapeFieldName(s.Name, separator))
+		}
+	}
+	return b.String()
+}
+
+// String returns the path formatted with DefaultSeparator.
+func (p Path) String() string {
+	return p.Format(DefaultSeparator)
+}
+
+// ParsePath parses a field name, such as one returned by Hush, into a Path.
+// Brackets holding digits only are read as indexes.
+func ParsePath(fieldName, separator string) (Path, error) {
+	return parsePath(fieldName, separator, nil)
+}
+
+// ParsePathOf parses a field name returned by Hush for a value of type t, hushed under
+// name ("" if none was given), into a Path like ParsePath does, but reads the brackets
+// following a map as keys even when they hold digits only, so the keys of a map[int]T
+// aren't mistaken for indexes. Below interface values, whose dynamic type isn't known,
+// brackets are read as ParsePath reads them.
+func ParsePathOf(fieldName, separator, name string, t reflect.Type) (Path, error) {
+	prefix, err := ParsePath(name, separator)
+	if err != nil {
+		return nil, err
+	}
+	return parsePath(fieldName, separator, func(path Path) bool {
+		if len(path) < len(prefix) {
+			return false
+		}
+		t := typeAt(t, path[len(prefix):])
+		return t != nil && t.Kind() == reflect.Map
+	})
+}
+
+// typeAt returns the type of the value at path below a value of type t, or nil if it
+// isn't known statically.
+func typeAt(t reflect.Type, path Path) reflect.Type {
+	for _, s := range path {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil {
+			return nil
+		}
+		switch {
+		case s.Kind == SegmentField && t.Kind() == reflect.Struct:
+			field, ok := t.FieldByName(s.Name)
+			if !ok {
+				return nil
+			}
+			t = field.Type
+		case s.Kind != SegmentField && (t.Kind() == reflect.Map || t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// parsePath parses fieldName into a Path. Brackets holding digits only are read as
+// indexes, unless isKey, if set, reports that the path read so far leads to a map.
+func parsePath(fieldName, separator string, isKey func(path Path) bool) (Path, error) {
+	var (
+		path      Path
+		name      strings.Builder
+		inField   bool // a field segment is being read
+		separated bool // the last token read is a separator
+	)
+	flush := func() {
+		if inField {
+			path = append(path, Segment{Kind: SegmentField, Name: name.String()})
+			name.Reset()
+			inField = false
+		}
+	}
+
+	for i := 0; i < len(fieldName); i++ {
+		separated = false
+		switch c := fieldName[i]; {
+		case c == '\\':
+			if i+1 == len(fieldName) {
+				return nil, fmt.Errorf("hush: invalid path %q: trailing backslash", fieldName)
+			}
+			i++
+			name.WriteByte(fieldName[i])
+			inField = true
+		case c == '[':
+			flush()
+			content, end, err := readBracket(fieldName, i+1)
+			if err != nil {
+				return nil, err
+			}
+			// Escaped digits, as in "[\12]", are always a key.
+			if index, err := strconv.Atoi(content); err == nil && isDigits(fieldName[i+1:end]) && (isKey == nil || !isKey(path)) {
+				path = append(path, Segment{Kind: SegmentIndex, Index: index})
+			} else {
+				path = append(path, Segment{Kind: SegmentKey, Name: content})
+			}
+			i = end
+		case separator != "" && strings.HasPrefix(fieldName[i:], separator):
+			if !inField && (len(path) == 0 || path[len(path)-1].Kind == SegmentField) {
+				return nil, fmt.Errorf("hush: invalid path %q: empty field name at offset %d", fieldName, i)
+			}
+			flush()
+			i += len(separator) - 1
+			separated = true
+		default:
+			name.WriteByte(c)
+			inField = true
+		}
+	}
+	if separated {
+		return nil, fmt.Errorf("hush: invalid path %q: trailing separator", fieldName)
+	}
+	flush()
+	return path, nil
+}
+
+// readBracket reads the unescaped contents of the bracket starting at offset start of
+// fieldName, and returns them along with the offset of the closing bracket.
+func readBracket(fieldName string, start int) (string, int, error) {
+	var content strings.Builder
+	for i := start; i < len(fieldName); i++ {
+		switch fieldName[i] {
+		case '\\':
+			if i+1 == len(fieldName) {
+				return "", 0, fmt.Errorf("hush: invalid path %q: trailing backslash", fieldName)
+			}
+			i++
+			content.WriteByte(fieldName[i])
+		case ']':
+			return content.String(), i, nil
+		default:
+			content.WriteByte(fieldName[i])
+		}
+	}
+	return "", 0, fmt.Errorf("hush: invalid path %q: unterminated bracket at offset %d", fieldName, start-1)
+}
+
+// keyPath returns the field name of the map entry with the given key.
+func keyPath(fieldName, key string) string {
+	return fieldName + "[" + escapeKey(key) + "]"
+}
+
+// keyEscaper escapes the characters of map keys that would be read as path syntax.
+var keyEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+// escapeKey escapes a map key for use in a path.
+func escapeKey(key string) string {
+	if !strings.ContainsAny(key, `\[]`) {
+		return key
+	}
+	return keyEscaper.Replace(key)
+}
+
+// escapeFieldName escapes a field name for use in a path with the given separator.
+func escapeFieldName(name, separator string) string {
+	if !strings.ContainsAny(name, `\[]`) && (separator == "" || !strings.Contains(name, separator)) {
+		return name
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch {
+		case name[i] == '\\' || name[i] == '[' || name[i] == ']':
+			b.WriteByte('\\')
+			b.WriteByte(name[i])
+		case separator != "" && strings.HasPrefix(name[i:], separator):
+			for j := 0; j < len(separator); j++ {
+				b.WriteByte('\\')
+				b.WriteByte(separator[j])
+			}
+			i += len(separator) - 1
+		default:
+			b.WriteByte(name[i])
+		}
+	}
+	return b.String()
+}
+
+func isDigits(s string) bool {
+	return s != "" && countDigits(s) == len(s)
+}
@@ -0,0 +1,223 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		separator string
+		want      Path
+		wantErr   bool
+	}{
+		{"Empty", "", ".", nil, false},
+		{"Field", "Name", ".", Path{{Kind: SegmentField, Name: "Name"}}, false},
+		{
+			name:      "Nested",
+			fieldName: "users[0].tags[admin]",
+			separator: ".",
+			want: Path{
+				{Kind: SegmentField, Name: "users"},
+				{Kind: SegmentIndex, Index: 0},
+				{Kind: SegmentField, Name: "tags"},
+				{Kind: SegmentKey, Name: "admin"},
+			},
+		},
+		{
+			name:      "Root slice",
+			fieldName: "[1][x.y]",
+			separator: ".",
+			want:      Path{{Kind: SegmentIndex, Index: 1}, {Kind: SegmentKey, Name: "x.y"}},
+		},
+		{
+			name:      "Escaped key",
+			fieldName: `m[a\]b\\]`,
+			separator: ".",
+			want:      Path{{Kind: SegmentField, Name: "m"}, {Kind: SegmentKey, Name: `a]b\`}},
+		},
+		{
+			name:      "Escaped separator",
+			fieldName: `a\:\:b::c`,
+			separator: "::",
+			want:      Path{{Kind: SegmentField, Name: "a::b"}, {Kind: SegmentField, Name: "c"}},
+		},
+		{"Escaped digits are a key", `m[\12]`, ".", Path{{Kind: SegmentField, Name: "m"}, {Kind: SegmentKey, Name: "12"}}, false},
+		{"Negative index is a key", "m[-1]", ".", Path{{Kind: SegmentField, Name: "m"}, {Kind: SegmentKey, Name: "-1"}}, false},
+		{"Unterminated bracket", "m[a", ".", nil, true},
+		{"Trailing backslash", `m\`, ".", nil, true},
+		{"Empty field", "a..b", ".", nil, true},
+		{"Trailing separator", "a.", ".", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePath(tt.fieldName, tt.separator)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePath() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      Path
+		separator string
+		want      string
+	}{
+		{"Empty", nil, ".", ""},
+		{
+			name: "Nested",
+			path: Path{
+				{Kind: SegmentField, Name: "users"},
+				{Kind: SegmentIndex, Index: 2},
+				{Kind: SegmentField, Name: "tags"},
+				{Kind: SegmentKey, Name: "a[b]"},
+			},
+			separator: "_",
+			want:      `users[2]_tags[a\[b\]]`,
+		},
+		{
+			name:      "Separator in field",
+			path:      Path{{Kind: SegmentField, Name: "a.b"}, {Kind: SegmentField, Name: "c"}},
+			separator: ".",
+			want:      `a\.b.c`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.path.Format(tt.separator)
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+			parsed, err := ParsePath(got, tt.separator)
+			if err != nil {
+				t.Fatalf("ParsePath() error = %v", err)
+			}
+			if !reflect.DeepEqual(parsed, tt.path) {
+				t.Errorf("ParsePath(Format()) = %+v, want %+v", parsed, tt.path)
+			}
+		})
+	}
+}
+
+func TestHushEscapesMapKeys(t *testing.T) {
+	input := map[string]string{"a]b": "secret", "a": "public"}
+
+	got, err := NewHush().Hush(context.Background(), input, "m", Rule{Path: `m[a\]b]`, Type: TagHide})
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{{`m[a\]b]`, HiddenValue}, {"m[a]", "public"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hush() = %v, want %v", got, want)
+	}
+
+	path, err := ParsePath(got[0][0], DefaultSeparator)
+	if err != nil {
+		t.Fatalf("ParsePath() error = %v", err)
+	}
+	if key := path[len(path)-1]; key.Kind != SegmentKey || key.Name != "a]b" {
+		t.Errorf("ParsePath() last segment = %+v, want key a]b", key)
+	}
+}
+
+func TestHushPathsRoundTrip(t *testing.T) {
+	type user struct {
+		First_Name string
+		Scores     map[int]int
+		Tags       []string
+	}
+	input := struct{ User user }{User: user{First_Name: "John", Scores: map[int]int{12: 1}, Tags: []string{"a"}}}
+
+	got, err := NewHush().Hush(context.Background(), input, WithSeparator("_"))
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{{`User_First\_Name`, "John"}, {"User_Scores[12]", "1"}, {"User_Tags[0]", "a"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Hush() = %v, want %v", got, want)
+	}
+
+	paths := []Path{
+		{{Kind: SegmentField, Name: "User"}, {Kind: SegmentField, Name: "First_Name"}},
+		{{Kind: SegmentField, Name: "User"}, {Kind: SegmentField, Name: "Scores"}, {Kind: SegmentKey, Name: "12"}},
+		{{Kind: SegmentField, Name: "User"}, {Kind: SegmentField, Name: "Tags"}, {Kind: SegmentIndex, Index: 0}},
+	}
+	for i, row := range got {
+		path, err := ParsePathOf(row[0], "_", "", reflect.TypeOf(input))
+		if err != nil {
+			t.Fatalf("ParsePathOf(%q) error = %v", row[0], err)
+		}
+		if !reflect.DeepEqual(path, paths[i]) {
+			t.Errorf("ParsePathOf(%q) = %+v, want %+v", row[0], path, paths[i])
+		}
+		if formatted := path.Format("_"); formatted != row[0] {
+			t.Errorf("Format() = %q, want %q", formatted, row[0])
+		}
+	}
+}
+
+func TestParsePathOf(t *testing.T) {
+	type item struct {
+		Counts map[int][]int
+		Any    interface{}
+	}
+	tests := []struct {
+		name      string
+		fieldName string
+		prefix    string
+		typ       reflect.Type
+		want      Path
+	}{
+		{
+			name:      "Digits after a map are a key",
+			fieldName: "Counts[12][3]",
+			typ:       reflect.TypeOf(item{}),
+			want:      Path{{Kind: SegmentField, Name: "Counts"}, {Kind: SegmentKey, Name: "12"}, {Kind: SegmentIndex, Index: 3}},
+		},
+		{
+			name:      "Pointers are followed",
+			fieldName: "[12]",
+			typ:       reflect.TypeOf(&map[int]string{}),
+			want:      Path{{Kind: SegmentKey, Name: "12"}},
+		},
+		{
+			name:      "Prefix is skipped",
+			fieldName: "items[0].Counts[12]",
+			prefix:    "items",
+			typ:       reflect.TypeOf([]item{}),
+			want:      Path{{Kind: SegmentField, Name: "items"}, {Kind: SegmentIndex, Index: 0}, {Kind: SegmentField, Name: "Counts"}, {Kind: SegmentKey, Name: "12"}},
+		},
+		{
+			name:      "Digits below an interface are an index",
+			fieldName: "Any[12]",
+			typ:       reflect.TypeOf(item{}),
+			want:      Path{{Kind: SegmentField, Name: "Any"}, {Kind: SegmentIndex, Index: 12}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePathOf(tt.fieldName, DefaultSeparator, tt.prefix, tt.typ)
+			if err != nil {
+				t.Fatalf("ParsePathOf() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePathOf() = %+v, want %+v", got, tt.want)
+			}
+			if formatted := got.Format(DefaultSeparator); formatted != tt.fieldName {
+				t.Errorf("Format() = %q, want %q", formatted, tt.fieldName)
+			}
+		})
+	}
+}
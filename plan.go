@@ -0,0 +1,89 @@
+package hush
+
+import (
+	"reflect"
+	"sync"
+)
+
+// structPlan is the reflection data of a struct type needed to process its values,
+// computed once per type and cached in structPlans.
+type structPlan struct {
+	fields   []fieldPlan
+	exported int
+	// weight estimates the work needed to process a value of the type: the number of
+	// fields in its subtree, counting the element type of collections once, capped at maxPlanWeight.
+	weight int
+}
+
+// maxPlanWeight caps the weight of a struct plan.
+const maxPlanWeight = 1 << 10
+
+// fieldPlan describes a single field of a struct type.
+type fieldPlan struct {
+	field    reflect.StructField
+	index    int
+	exported bool
+	tag      string
+	// leaf is set for fields of basic kinds, which never need to be descended into.
+	leaf bool
+	// envName and envPrefix are read from the env, envconfig and envPrefix tags, see WithEnvNames.
+	envName   string
+	envPrefix string
+}
+
+// structPlans caches the plan of every struct type processed so far, keyed by reflect.Type.
+var structPlans sync.Map
+
+// planFor returns the plan of the struct type t, computing and caching it on first use.
+func planFor(t reflect.Type) *structPlan {
+	if cached, ok := structPlans.Load(t); ok {
+		return cached.(*structPlan)
+	}
+
+	plan := &structPlan{fields: make([]fieldPlan, t.NumField())}
+	for i := range plan.fields {
+		field := t.Field(i)
+		plan.fields[i] = fieldPlan{
+			field:     field,
+			index:     i,
+			exported:  field.IsExported(),
+			tag:       field.Tag.Get("hush"),
+			leaf:      isBasicTypeKind(field.Type.Kind()),
+			envName:   envName(field.Tag),
+			envPrefix: field.Tag.Get("envPrefix"),
+		}
+		if field.IsExported() {
+			plan.exported++
+		}
+	}
+	plan.weight = typeWeight(t, make(map[reflect.Type]bool))
+
+	cached, _ := structPlans.LoadOrStore(t, plan)
+	return cached.(*structPlan)
+}
+
+// typeWeight returns the number of fields in the subtree of t, capped at maxPlanWeight.
+// Types already being visited count as a single field so recursive types terminate.
+func typeWeight(t reflect.Type, visiting map[reflect.Type]bool) int {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return typeWeight(t.Elem(), visiting)
+	case reflect.Struct:
+		if visiting[t] {
+			return 1
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		weight := 0
+		for i := 0; i < t.NumField() && weight < maxPlanWeight; i++ {
+			weight += typeWeight(t.Field(i).Type, visiting)
+		}
+		if weight > maxPlanWeight {
+			weight = maxPlanWeight
+		}
+		return weight
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,33 @@
+package hush
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanFor(t *testing.T) {
+	typ := reflect.TypeOf(testStruct{})
+
+	plan := planFor(typ)
+	if len(plan.fields) != typ.NumField() {
+		t.Fatalf("planFor() has %d fields, want %d", len(plan.fields), typ.NumField())
+	}
+	if plan.exported != 5 {
+		t.Errorf("planFor() exported = %d, want 5", plan.exported)
+	}
+
+	public := plan.fields[0]
+	if public.field.Name != "PublicField" || !public.exported || public.tag != "mask" || !public.leaf {
+		t.Errorf("planFor() PublicField = %+v, want exported leaf with mask tag", public)
+	}
+	if private := plan.fields[1]; private.exported || private.tag != "mask" {
+		t.Errorf("planFor() privateField = %+v, want unexported with mask tag", private)
+	}
+	if nested := plan.fields[2]; nested.leaf || nested.tag != "" {
+		t.Errorf("planFor() NestedStruct = %+v, want untagged non-leaf", nested)
+	}
+
+	if again := planFor(typ); again != plan {
+		t.Errorf("planFor() returned a new plan for a cached type")
+	}
+}
@@ -5,157 +5,208 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
-	"sync"
 )
 
 const (
-	// maxRecursionDepth guards against stack overflow from circular pointer references.
+	// maxRecursionDepth is the default limit on how deeply values are descended into, see WithMaxDepth.
 	maxRecursionDepth = 64
 
-	// maxConcurrency limits the number of goroutines spawned for concurrent struct field processing.
+	// maxConcurrency limits the number of goroutines spawned for a single value in ConcurrencyAuto mode.
 	maxConcurrency = 64
 )
 
 // processValue is the main function for processing individual fields.
 // It handles different types of fields (struct, pointer, slice, map, etc.) and applies the appropriate masking.
-// inheritedTag carries the hush tag from a parent field (e.g., through pointer dereference) so it isn't lost.
-func (ht *hushType) processValue(ctx context.Context, fieldName string, field reflect.StructField, value reflect.Value, opts *hushOptions, inheritedTag string, depth int) ([][]string, error) {
-	if depth > maxRecursionDepth {
-		return [][]string{{fieldName, "[max depth exceeded]"}}, nil
+// inherited carries the hush tag from a parent field (e.g., through pointer dereference) so it isn't lost.
+// visited holds the pointers enclosing the value, to detect cycles.
+func (ht *hushType) processValue(ctx context.Context, fieldName string, field reflect.StructField, value reflect.Value, opts *hushOptions, inherited origin, depth int, visited *visit) ([]Field, error) {
+	fp := &fieldPlan{field: field, exported: field.PkgPath == "", tag: field.Tag.Get("hush")}
+	return ht.processField(ctx, fieldName, fp, value, opts, inherited, depth, visited)
+}
+
+// processField is processValue for a field whose plan is already known, so struct fields
+// don't have their tags read again on every call.
+func (ht *hushType) processField(ctx context.Context, fieldName string, fp *fieldPlan, value reflect.Value, opts *hushOptions, inherited origin, depth int, visited *visit) ([]Field, error) {
+	o := origin{tag: fp.tag, source: SourceTag, private: inherited.private || !fp.exported, envPrefix: inherited.envPrefix + fp.envPrefix}
+	if depth > opts.depthLimit() {
+		return []Field{newField(fieldName, "[max depth exceeded]", value, "", SourceDefault, o.private)}, nil
 	}
 
-	hushTag := field.Tag.Get("hush")
-	if hushTag == "" {
-		hushTag = inheritedTag
+	if o.tag == "" {
+		o.tag, o.source = opts.matchRule(fieldName), SourceRule
+	}
+	if o.tag == "" {
+		o.tag, o.source = inherited.tag, inherited.source
+	}
+	if err := opts.checkHushTag(fieldName, o.tag); err != nil {
+		return nil, err
 	}
 
-	if field.PkgPath != "" && !opts.includePrivate {
+	if !fp.exported && !opts.includePrivate {
 		return nil, nil // Skip unexported fields when not including private fields
 	}
 
-	if hushTag == string(TagRemove) {
+	if tagAction(o.tag) == TagRemove {
 		return nil, nil
 	}
 
+	if leaf, leafTag, ok := resolveLeaf(ctx, value); ok {
+		if o.tag == "" {
+			o.tag, o.source = string(leafTag), SourceTag
+		}
+		if err := opts.checkHushTag(fieldName, o.tag); err != nil {
+			return nil, err
+		}
+		if tagAction(o.tag) == TagRemove {
+			return nil, nil
+		}
+		return processString(fieldName, leaf, value, o, opts), nil
+	}
+
+	if fp.leaf {
+		return ht.processSimpleField(fieldName, fp.field, value, o, opts)
+	}
+
 	switch value.Kind() {
 	case reflect.Struct:
-		return ht.processStruct(ctx, value, fieldName, opts, depth+1)
+		return ht.processStruct(ctx, value, fieldName, opts, o, depth+1, visited)
 	case reflect.Ptr:
 		if value.IsNil() {
-			return [][]string{{fieldName, "nil"}}, nil
+			return []Field{newField(fieldName, "nil", value, "", SourceDefault, o.private)}, nil
 		}
-		return ht.processValue(ctx, fieldName, reflect.StructField{}, value.Elem(), opts, hushTag, depth+1)
+		if ancestor, ok := visited.find(value); ok {
+			return []Field{newField(fieldName, cycleMarker(ancestor.path), value, "", SourceDefault, o.private)}, nil
+		}
+		return ht.processValue(ctx, fieldName, reflect.StructField{}, value.Elem(), opts, o, depth+1, newVisit(value, fieldName, visited))
+	case reflect.Interface:
+		// Interface values are processed as their dynamic type, keeping the tag like pointers do.
+		if value.IsNil() {
+			return []Field{newField(fieldName, "nil", value, "", SourceDefault, o.private)}, nil
+		}
+		return ht.processValue(ctx, fieldName, reflect.StructField{}, value.Elem(), opts, o, depth+1, visited)
 	case reflect.Slice, reflect.Array:
-		return ht.processSliceOrArray(ctx, fieldName, value, opts, hushTag, depth+1)
+		return ht.processSliceOrArray(ctx, fieldName, value, opts, o, depth+1, visited)
 	case reflect.Map:
-		return ht.processMap(ctx, fieldName, value, opts, hushTag, depth+1)
+		return ht.processMap(ctx, fieldName, value, opts, o, depth+1, visited)
 	default:
-		return ht.processSimpleField(fieldName, field, value, hushTag, opts)
+		return ht.processSimpleField(fieldName, fp.field, value, o, opts)
 	}
 }
 
 // processStruct handles the processing of struct fields.
-func (ht *hushType) processStruct(ctx context.Context, rv reflect.Value, prefix string, opts *hushOptions, depth int) ([][]string, error) {
-	data := make([][]string, 0, rv.NumField())
-	errChan := make(chan error, rv.NumField())
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	sem := make(chan struct{}, maxConcurrency)
-
-	t := rv.Type()
+// Hush tags aren't inherited by the fields, whether the struct is private is.
+func (ht *hushType) processStruct(ctx context.Context, rv reflect.Value, prefix string, opts *hushOptions, o origin, depth int, visited *visit) ([]Field, error) {
+	plan := planFor(rv.Type())
+
+	if opts.includePrivate && !rv.CanAddr() && rv.CanInterface() {
+		// Unexported fields can only be read through an addressable copy.
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
 
-	for i := 0; i < rv.NumField(); i++ {
-		field := t.Field(i)
-		value := rv.Field(i)
+	sorted := opts.order.sortsRows()
+	if sorted {
+		opts = opts.collecting() // The rows of the struct are only complete once sorted
+	}
 
-		if !opts.includePrivate && !field.IsExported() {
-			continue
+	fields := make([]*fieldPlan, 0, len(plan.fields))
+	for i := range plan.fields {
+		if opts.includePrivate || plan.fields[i].exported {
+			fields = append(fields, &plan.fields[i])
 		}
-
-		wg.Add(1)
-		sem <- struct{}{} // acquire semaphore slot
-		go func(field reflect.StructField, value reflect.Value) {
-			defer wg.Done()
-			defer func() { <-sem }() // release semaphore slot
-
-			select {
-			case <-ctx.Done():
-				errChan <- ctx.Err()
-				return
-			default:
-			}
-
-			fieldName := buildFieldName(prefix, field.Name, opts.separator)
-
-			result, err := ht.processValue(ctx, fieldName, field, value, opts, "", depth)
-			if err != nil {
-				errChan <- err
-				return
-			}
-
-			mu.Lock()
-			data = append(data, result...)
-			mu.Unlock()
-		}(field, value)
 	}
 
-	wg.Wait()
-	close(errChan)
-
-	var firstErr error
-	for err := range errChan {
-		firstErr = err
-		break
+	inherited := origin{private: o.private, envPrefix: o.envPrefix}
+	data, err := forEach(ctx, opts, len(fields), opts.fanOutStruct(plan), func(i int) ([]Field, error) {
+		fp := fields[i]
+		fieldName := buildFieldName(prefix, escapeFieldName(fp.field.Name, opts.separator), opts.separator)
+		if opts.envNames && fp.envName != "" {
+			fieldName = escapeFieldName(o.envPrefix+fp.envName, opts.separator)
+		}
+		return ht.processField(ctx, fieldName, fp, rv.Field(fp.index), opts, inherited, depth, visited)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if firstErr != nil {
-		return nil, firstErr
+	if sorted {
+		sortFields(data, opts.order)
 	}
 
-	sort.Slice(data, func(i, j int) bool {
-		return data[i][0] < data[j][0]
-	})
-
 	return data, nil
 }
 
 // processSimpleField handles the processing of simple (non-composite) fields.
-func (ht *hushType) processSimpleField(fieldName string, field reflect.StructField, value reflect.Value, hushTag string, opts *hushOptions) ([][]string, error) {
+func (ht *hushType) processSimpleField(fieldName string, field reflect.StructField, value reflect.Value, o origin, opts *hushOptions) ([]Field, error) {
 	if field.PkgPath != "" {
 		// This is an unexported field
 		if opts.includePrivate {
-			return processNonComposite(fieldName, value, hushTag, opts)
+			return processNonComposite(fieldName, value, o, opts)
 		}
 		return nil, nil // Skip unexported fields when not including private fields
 	}
 
 	// For exported fields, use Interface() as before
-	return processNonComposite(fieldName, value, hushTag, opts)
+	return processNonComposite(fieldName, value, o, opts)
 }
 
-func processNonComposite(fieldName string, value reflect.Value, hushTag string, opts *hushOptions) ([][]string, error) {
+func processNonComposite(fieldName string, value reflect.Value, o origin, opts *hushOptions) ([]Field, error) {
 	convertedString := convertNonCompositeToString(value)
-	return processString(fieldName, convertedString, hushTag, opts), nil
+	return processString(fieldName, convertedString, value, o, opts), nil
 }
 
 // processString applies the masking function to string values if needed.
-func processString(fieldName, value, hushTag string, opts *hushOptions) [][]string {
-	if opts.hushType != "" {
-		hushTag = string(opts.hushType)
-	}
+// rv is the original value, which value is the string form of.
+func processString(fieldName, value string, rv reflect.Value, o origin, opts *hushOptions) []Field {
+	hushTag, source := resolveOrigin(value, o, opts)
+	return []Field{newField(fieldName, applyHushTag(value, hushTag, opts), rv, hushTag, source, o.private)}
+}
 
-	if hushTag == string(TagHide) {
-		value = HiddenValue
-	} else if hushTag == string(TagMask) && opts.maskFunc != nil {
-		value = opts.maskFunc(value)
+// hushString returns value with the hush tag (or the hush type set in opts) applied.
+func hushString(value, hushTag string, opts *hushOptions) string {
+	return applyHushTag(value, resolveHushTag(value, hushTag, opts), opts)
+}
+
+// applyHushTag returns value with the resolved hush tag applied.
+func applyHushTag(value, hushTag string, opts *hushOptions) string {
+	directive, err := parseHushTag(hushTag)
+	if err != nil {
+		return HiddenValue // Invalid tags are rejected before reaching here, fail closed regardless
 	}
+	return directive.apply(value, opts)
+}
 
-	if fieldName == "" {
-		return [][]string{{value}}
+// isHushingTag reports whether the hush tag replaces the value of a field.
+func isHushingTag(hushTag string) bool {
+	switch tagAction(hushTag) {
+	case TagMask, TagHide, TagHash, TagEncrypt:
+		return true
+	default:
+		return false
 	}
+}
+
+// resolveHushTag returns the hush tag that applies to value: the hush type set in opts,
+// then the field's own tag, then TagMask if one of the detectors recognises the value.
+func resolveHushTag(value, hushTag string, opts *hushOptions) string {
+	hushTag, _ = resolveOrigin(value, origin{tag: hushTag}, opts)
+	return hushTag
+}
 
-	return [][]string{{fieldName, value}}
+// resolveOrigin is resolveHushTag for a value of the given origin, which also returns
+// where the resolved tag comes from.
+func resolveOrigin(value string, o origin, opts *hushOptions) (string, Source) {
+	switch {
+	case opts.hushType != "":
+		return string(opts.hushType), SourceDefault
+	case o.tag == "" && opts.detect(value):
+		return string(TagMask), SourceDetector
+	case o.tag == "":
+		return "", SourceDefault
+	default:
+		return o.tag, o.source
+	}
 }
 
 func convertNonCompositeToString(value reflect.Value) string {
@@ -172,63 +223,56 @@ func convertNonCompositeToString(value reflect.Value) string {
 }
 
 // processSliceOrArray handles the processing of slice or array fields.
-func (ht *hushType) processSliceOrArray(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, hushTag string, depth int) ([][]string, error) {
+func (ht *hushType) processSliceOrArray(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, o origin, depth int, visited *visit) ([]Field, error) {
 	// Handle basic types more elegantly
-	if isBasicType := isBasicTypeKind(value.Type().Elem().Kind()); isBasicType {
-		return ht.processBasicTypeSlice(fieldName, value, opts, hushTag)
+	elemType := value.Type().Elem()
+	if isBasicType := isBasicTypeKind(elemType.Kind()); isBasicType && !hasLeafMethods(elemType) {
+		return ht.processBasicTypeSlice(ctx, fieldName, value, opts, o)
 	}
 
-	return ht.processComplexTypeSlice(ctx, fieldName, value, opts, hushTag, depth)
+	return ht.processComplexTypeSlice(ctx, fieldName, value, opts, o, depth, visited)
 }
 
 // Helper function to determine if a kind is a basic type
 func isBasicTypeKind(kind reflect.Kind) bool {
-	basicTypes := map[reflect.Kind]bool{
-		reflect.String:  true,
-		reflect.Bool:    true,
-		reflect.Int:     true,
-		reflect.Int8:    true,
-		reflect.Int16:   true,
-		reflect.Int32:   true,
-		reflect.Int64:   true,
-		reflect.Uint:    true,
-		reflect.Uint8:   true,
-		reflect.Uint16:  true,
-		reflect.Uint32:  true,
-		reflect.Uint64:  true,
-		reflect.Float32: true,
-		reflect.Float64: true,
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
 	}
-	return basicTypes[kind]
 }
 
-// Process slices of basic types
-func (ht *hushType) processBasicTypeSlice(fieldName string, value reflect.Value, opts *hushOptions, hushTag string) ([][]string, error) {
-	result := make([][]string, 0, value.Len())
-	for i := 0; i < value.Len(); i++ {
+// Process slices of basic types. Their rows are passed on one at a time when streaming.
+func (ht *hushType) processBasicTypeSlice(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, o origin) ([]Field, error) {
+	processElem := func(i int) ([]Field, error) {
 		elemFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
+		elem := origin{tag: opts.matchRule(elemFieldName), source: SourceRule, private: o.private}
+		if elem.tag == "" {
+			elem.tag, elem.source = o.tag, o.source
+		}
+		if err := opts.checkHushTag(elemFieldName, elem.tag); err != nil {
+			return nil, err
+		}
+		if tagAction(elem.tag) == TagRemove {
+			return nil, nil
+		}
+
 		elemValue := value.Index(i)
 		convertedString := convertNonCompositeToString(elemValue)
-		elemResult := processString(elemFieldName, convertedString, hushTag, opts)
-		result = append(result, elemResult...)
+		return processString(elemFieldName, convertedString, elemValue, elem, opts), nil
 	}
-	return result, nil
-}
 
-// Process slices of complex types
-func (ht *hushType) processComplexTypeSlice(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, hushTag string, depth int) ([][]string, error) {
-	result := make([][]string, 0, value.Len())
-	for i := 0; i < value.Len(); i++ {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		elemFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
-		elemValue := value.Index(i)
+	if opts.emit != nil {
+		return nil, streamEach(ctx, opts, value.Len(), processElem)
+	}
 
-		elemResult, err := ht.processValue(ctx, elemFieldName, reflect.StructField{}, elemValue, opts, hushTag, depth)
+	result := make([]Field, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		elemResult, err := processElem(i)
 		if err != nil {
 			return nil, err
 		}
@@ -237,31 +281,45 @@ func (ht *hushType) processComplexTypeSlice(ctx context.Context, fieldName strin
 	return result, nil
 }
 
+// Process slices of complex types
+func (ht *hushType) processComplexTypeSlice(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, o origin, depth int, visited *visit) ([]Field, error) {
+	return forEach(ctx, opts, value.Len(), opts.fanOutElements(value.Len()), func(i int) ([]Field, error) {
+		elemFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
+		return ht.processValue(ctx, elemFieldName, reflect.StructField{}, value.Index(i), opts, o, depth, visited)
+	})
+}
+
 // processMap handles the processing of map fields.
-func (ht *hushType) processMap(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, hushTag string, depth int) ([][]string, error) {
-	result := make([][]string, 0, value.Len())
-	for _, key := range value.MapKeys() {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+func (ht *hushType) processMap(ctx context.Context, fieldName string, value reflect.Value, opts *hushOptions, o origin, depth int, visited *visit) ([]Field, error) {
+	sorted := opts.order.sortsRows()
+	if sorted {
+		opts = opts.collecting()
+	}
 
-		keyStr := fmt.Sprintf("%v", key.Interface())
-		mapFieldName := fieldName + "[" + keyStr + "]"
-		fieldValue := value.MapIndex(key)
+	keys := value.MapKeys()
+	keyStrs := make([]string, len(keys))
+	for i, key := range keys {
+		keyStrs[i] = fmt.Sprint(key) // fmt reads values of unexported fields, Interface() panics on them
+	}
+	if opts.order == OrderDeclaration {
+		// Maps have no declaration order, their entries are sorted by key instead.
+		sort.Sort(keysByName{keys: keys, names: keyStrs})
+	}
 
-		processedValue, err := ht.processValue(ctx, mapFieldName, reflect.StructField{}, fieldValue, opts, hushTag, depth)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, processedValue...)
+	parallel := !isBasicTypeKind(value.Type().Elem().Kind()) && opts.fanOutElements(len(keys))
+
+	result, err := forEach(ctx, opts, len(keys), parallel, func(i int) ([]Field, error) {
+		mapFieldName := keyPath(fieldName, keyStrs[i])
+		return ht.processValue(ctx, mapFieldName, reflect.StructField{}, value.MapIndex(keys[i]), opts, o, depth, visited)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Sort the result to ensure consistent order
-	sort.Slice(result, func(i, j int) bool {
-		return result[i][0] < result[j][0]
-	})
+	if sorted {
+		sortFields(result, opts.order)
+	}
 
 	return result, nil
 }
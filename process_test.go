@@ -43,12 +43,12 @@ func TestProcessField(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ht.processValue(context.Background(), tt.fieldName, tt.field, reflect.ValueOf(tt.value), tt.opts, "", 0)
+			got, err := ht.processValue(context.Background(), tt.fieldName, tt.field, reflect.ValueOf(tt.value), tt.opts, origin{}, 0, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processField() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if got := fieldRows(got); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("processField() = %v, want %v", got, tt.want)
 			}
 		})
@@ -80,12 +80,12 @@ func TestProcessSimpleField(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ht.processSimpleField(tt.fieldName, tt.field, reflect.ValueOf(tt.value), tt.hushTag, tt.opts)
+			got, err := ht.processSimpleField(tt.fieldName, tt.field, reflect.ValueOf(tt.value), origin{tag: tt.hushTag}, tt.opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processSimpleField() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if got := fieldRows(got); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("processSimpleField() = %v, want %v", got, tt.want)
 			}
 		})
@@ -129,7 +129,7 @@ func TestProcessString(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := processString(tt.fieldName, tt.value, tt.hushTag, tt.opts)
+			got := fieldRows(processString(tt.fieldName, tt.value, reflect.ValueOf(tt.value), origin{tag: tt.hushTag}, tt.opts))
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("processString() = %v, want %v", got, tt.want)
 			}
@@ -232,7 +232,8 @@ func TestProcessSliceOrArray(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ht.processSliceOrArray(context.Background(), tt.fieldName, reflect.ValueOf(tt.value), tt.opts, tt.hushTag, 0)
+			fields, err := ht.processSliceOrArray(context.Background(), tt.fieldName, reflect.ValueOf(tt.value), tt.opts, origin{tag: tt.hushTag}, 0, nil)
+			got := fieldRows(fields)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processSliceOrArray() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -323,14 +324,39 @@ func TestProcessBasicTypeSlice(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ht.processBasicTypeSlice(tt.fieldName, reflect.ValueOf(tt.value), tt.opts, tt.hushTag)
+			got, err := ht.processBasicTypeSlice(context.Background(), tt.fieldName, reflect.ValueOf(tt.value), tt.opts, origin{tag: tt.hushTag})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processBasicTypeSlice() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if got := fieldRows(got); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("processBasicTypeSlice() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestProcessMapInUnexportedField(t *testing.T) {
+	type holder struct {
+		i interface{}
+		m map[bool]string
+	}
+	input := holder{i: map[string]int{"k": 1}, m: map[bool]string{true: "b"}}
+
+	got, err := NewHush().Hush(context.Background(), input, WithPrivateFields(true))
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{{"i[k]", "1"}, {"m[true]", "b"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hush() = %v, want %v", got, want)
+	}
+
+	sanitized, err := Sanitize(context.Background(), input, WithPrivateFields(true))
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if !reflect.DeepEqual(sanitized, input) {
+		t.Errorf("Sanitize() = %+v, want %+v", sanitized, input)
+	}
+}
@@ -0,0 +1,239 @@
+package hush
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Renderer writes the rows produced by Hush to w in a specific format.
+// separator is the separator used to build the field names, so renderers
+// can re-inflate nested structures.
+type Renderer interface {
+	Render(w io.Writer, rows [][]string, separator string) error
+}
+
+// TextRenderer renders one "field: value" line per row.
+type TextRenderer struct{}
+
+// Render implements Renderer.
+func (TextRenderer) Render(w io.Writer, rows [][]string, _ string) error {
+	bw := bufio.NewWriter(w)
+	for _, row := range rows {
+		name, value := splitRow(row)
+		if name == "" {
+			fmt.Fprintln(bw, value)
+			continue
+		}
+		fmt.Fprintf(bw, "%s: %s\n", name, value)
+	}
+	return bw.Flush()
+}
+
+// JSONRenderer renders the rows as a flat JSON object keyed by field name, in row order.
+type JSONRenderer struct{}
+
+// Render implements Renderer.
+func (JSONRenderer) Render(w io.Writer, rows [][]string, _ string) error {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, row := range rows {
+		name, value := splitRow(row)
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(&buf, name)
+		buf.WriteByte(':')
+		writeJSONString(&buf, value)
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// NestedJSONRenderer renders the rows as a JSON object nested on the separator,
+// so "Address.City" becomes {"Address":{"City":...}}. Keys keep their row order.
+type NestedJSONRenderer struct{}
+
+// Render implements Renderer.
+func (NestedJSONRenderer) Render(w io.Writer, rows [][]string, separator string) error {
+	var buf bytes.Buffer
+	writeNestedJSON(&buf, buildFieldTree(normalizeRows(rows), separator))
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeNestedJSON writes the children of node as a JSON object.
+func writeNestedJSON(buf *bytes.Buffer, node *fieldNode) {
+	buf.WriteByte('{')
+	for i, child := range node.children {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(buf, child.name)
+		buf.WriteByte(':')
+		if child.leaf {
+			writeJSONString(buf, child.value)
+		} else {
+			writeNestedJSON(buf, child)
+		}
+	}
+	buf.WriteByte('}')
+}
+
+// YAMLRenderer renders the rows as YAML-like indented text nested on the separator.
+type YAMLRenderer struct{}
+
+// Render implements Renderer.
+func (YAMLRenderer) Render(w io.Writer, rows [][]string, separator string) error {
+	bw := bufio.NewWriter(w)
+	writeYAML(bw, buildFieldTree(normalizeRows(rows), separator), 0)
+	return bw.Flush()
+}
+
+// writeYAML writes the children of node, indented by two spaces per level.
+func writeYAML(w *bufio.Writer, node *fieldNode, level int) {
+	indent := strings.Repeat("  ", level)
+	for _, child := range node.children {
+		if child.leaf {
+			fmt.Fprintf(w, "%s%s: %s\n", indent, yamlScalar(child.name), yamlScalar(child.value))
+			continue
+		}
+		fmt.Fprintf(w, "%s%s:\n", indent, yamlScalar(child.name))
+		writeYAML(w, child, level+1)
+	}
+}
+
+// yamlScalar quotes s when it would not be read back as the same plain scalar.
+func yamlScalar(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#\n\r\t\"'") ||
+		strings.ContainsAny(s[:1], "-?[]{},&*!|>%@`") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// LogfmtRenderer renders the rows as a single logfmt line of key=value pairs.
+type LogfmtRenderer struct{}
+
+// Render implements Renderer.
+func (LogfmtRenderer) Render(w io.Writer, rows [][]string, _ string) error {
+	var buf bytes.Buffer
+	for i, row := range rows {
+		name, value := splitRow(row)
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(logfmtKey(name))
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(value))
+	}
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// logfmtKey replaces the characters that are not allowed in a logfmt key.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "value"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes the value when it contains spaces, quotes, equal signs or control characters.
+func logfmtValue(value string) string {
+	if strings.IndexFunc(value, func(r rune) bool { return r <= ' ' || r == '=' || r == '"' }) >= 0 {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// CSVRenderer renders the rows as two-column CSV records, optionally preceded by a header.
+type CSVRenderer struct {
+	Header bool
+}
+
+// Render implements Renderer.
+func (r CSVRenderer) Render(w io.Writer, rows [][]string, _ string) error {
+	cw := csv.NewWriter(w)
+	if r.Header {
+		if err := cw.Write([]string{"Field", "Value"}); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		name, value := splitRow(row)
+		if err := cw.Write([]string{name, value}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// TableRenderer renders the rows as a table using tablewriter.
+type TableRenderer struct{}
+
+// Render implements Renderer.
+func (TableRenderer) Render(w io.Writer, rows [][]string, _ string) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Field", "Value"})
+	table.AppendBulk(normalizeRows(rows))
+	table.Render()
+	return nil
+}
+
+// NestedMap re-inflates the rows into nested maps, splitting the field names on the separator.
+// Values are strings, nested structures are map[string]interface{}.
+func NestedMap(rows [][]string, separator string) map[string]interface{} {
+	return nestedMap(buildFieldTree(normalizeRows(rows), separator))
+}
+
+// nestedMap converts the children of node into a map.
+func nestedMap(node *fieldNode) map[string]interface{} {
+	m := make(map[string]interface{}, len(node.children))
+	for _, child := range node.children {
+		if child.leaf {
+			m[child.name] = child.value
+		} else {
+			m[child.name] = nestedMap(child)
+		}
+	}
+	return m
+}
+
+// splitRow returns the field name and value of a row. Rows of hushed values
+// without a prefix only hold the value and have an empty field name.
+func splitRow(row []string) (string, string) {
+	switch len(row) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", row[0]
+	default:
+		return row[0], row[1]
+	}
+}
+
+// normalizeRows returns the rows as two-column field name and value pairs.
+func normalizeRows(rows [][]string) [][]string {
+	normalized := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		name, value := splitRow(row)
+		normalized = append(normalized, []string{name, value})
+	}
+	return normalized
+}
@@ -0,0 +1,137 @@
+package hush
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var renderRows = [][]string{
+	{"Address.City", "Any town"},
+	{"Address.Street", "****"},
+	{"Name", "John"},
+	{"Password", "HIDDEN"},
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer Renderer
+		rows     [][]string
+		want     string
+	}{
+		{
+			name:     "Text",
+			renderer: TextRenderer{},
+			rows:     renderRows,
+			want:     "Address.City: Any town\nAddress.Street: ****\nName: John\nPassword: HIDDEN\n",
+		},
+		{
+			name:     "Text without field name",
+			renderer: TextRenderer{},
+			rows:     [][]string{{"HIDDEN"}},
+			want:     "HIDDEN\n",
+		},
+		{
+			name:     "JSON",
+			renderer: JSONRenderer{},
+			rows:     renderRows,
+			want:     `{"Address.City":"Any town","Address.Street":"****","Name":"John","Password":"HIDDEN"}` + "\n",
+		},
+		{
+			name:     "Nested JSON",
+			renderer: NestedJSONRenderer{},
+			rows:     renderRows,
+			want:     `{"Address":{"City":"Any town","Street":"****"},"Name":"John","Password":"HIDDEN"}` + "\n",
+		},
+		{
+			name:     "YAML",
+			renderer: YAMLRenderer{},
+			rows:     append(renderRows, []string{"Note", "a: b"}),
+			want:     "Address:\n  City: Any town\n  Street: \"****\"\nName: John\nPassword: HIDDEN\nNote: \"a: b\"\n",
+		},
+		{
+			name:     "Logfmt",
+			renderer: LogfmtRenderer{},
+			rows:     renderRows,
+			want:     `Address.City="Any town" Address.Street=**** Name=John Password=HIDDEN` + "\n",
+		},
+		{
+			name:     "CSV",
+			renderer: CSVRenderer{Header: true},
+			rows:     renderRows,
+			want:     "Field,Value\nAddress.City,Any town\nAddress.Street,****\nName,John\nPassword,HIDDEN\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.renderer.Render(&buf, tt.rows, "."); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (TableRenderer{}).Render(&buf, renderRows, "."); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"FIELD", "VALUE", "Address.City", "Any town", "HIDDEN"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Render() = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestNestedMap(t *testing.T) {
+	rows := [][]string{
+		{"Address_City", "Anytown"},
+		{"Tags[0]", "a"},
+		{"Name", "John"},
+	}
+	want := map[string]interface{}{
+		"Address": map[string]interface{}{"City": "Anytown"},
+		"Tags[0]": "a",
+		"Name":    "John",
+	}
+	if got := NestedMap(rows, "_"); !reflect.DeepEqual(got, want) {
+		t.Errorf("NestedMap() = %v, want %v", got, want)
+	}
+}
+
+func TestHushTo(t *testing.T) {
+	type user struct {
+		Name     string
+		Password string `hush:"hide"`
+	}
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want string
+	}{
+		{"Default renderer", nil, "Name: John\nPassword: HIDDEN\n"},
+		{"With renderer", []interface{}{WithRenderer(LogfmtRenderer{})}, "Name=John Password=HIDDEN\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := HushTo(context.Background(), &buf, user{Name: "John", Password: "secret"}, tt.args...)
+			if err != nil {
+				t.Fatalf("HushTo() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("HushTo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package hush
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rule applies a hush type to the fields whose path matches it, which lets you hush
+// fields of types you cannot add hush tags to.
+//
+// Path uses the same syntax as the field names returned by Hush, e.g. "users[0].password"
+// or "headers[Authorization]"; map keys and struct fields are interchangeable, so
+// "headers.Authorization" matches too. Matching is case-insensitive and "*" matches any
+// sequence of characters, including separators, so "*.password" matches "user.password"
+// and "*Token" matches "auth.AccessToken". Paths include the prefix, if any.
+//
+// If Regexp is set, it is matched against the field name as returned by Hush instead of Path.
+//
+// A field's hush tag always takes precedence over the rules, and a matching rule takes
+// precedence over the tag inherited from a parent field. When several rules match,
+// exact paths win over patterns and regular expressions, and among those the rule
+// added last wins.
+type Rule struct {
+	Path   string
+	Regexp *regexp.Regexp
+	Type   HushType
+}
+
+// ruleSet holds the rules of a call compiled for matching.
+type ruleSet struct {
+	separator string
+	exact     map[string]HushType
+	patterns  []compiledRule
+}
+
+// compiledRule is a pattern or regular expression rule ready for matching.
+type compiledRule struct {
+	pattern  string
+	re       *regexp.Regexp
+	hushType HushType
+}
+
+// compileRules compiles the rules, returning nil if there are none.
+func compileRules(rules []Rule, separator string) *ruleSet {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	rs := &ruleSet{separator: separator, exact: make(map[string]HushType)}
+	for _, rule := range rules {
+		switch {
+		case rule.Regexp != nil:
+			rs.patterns = append(rs.patterns, compiledRule{re: rule.Regexp, hushType: rule.Type})
+		case strings.Contains(rule.Path, "*"):
+			rs.patterns = append(rs.patterns, compiledRule{pattern: rs.canonical(rule.Path), hushType: rule.Type})
+		default:
+			rs.exact[rs.canonical(rule.Path)] = rule.Type
+		}
+	}
+	return rs
+}
+
+// match returns the hush type of the rule matching fieldName, or "" if none does.
+func (rs *ruleSet) match(fieldName string) string {
+	if rs == nil || fieldName == "" {
+		return ""
+	}
+
+	canonical := rs.canonical(fieldName)
+	if hushType, ok := rs.exact[canonical]; ok {
+		return string(hushType)
+	}
+
+	for i := len(rs.patterns) - 1; i >= 0; i-- {
+		rule := rs.patterns[i]
+		if rule.re != nil {
+			if rule.re.MatchString(fieldName) {
+				return string(rule.hushType)
+			}
+		} else if globMatch(rule.pattern, canonical) {
+			return string(rule.hushType)
+		}
+	}
+	return ""
+}
+
+// canonical returns the lower-cased segments of a field name joined by a NUL byte,
+// so that struct fields, map keys and indexes compare the same way.
+func (rs *ruleSet) canonical(fieldName string) string {
+	return strings.ToLower(strings.Join(splitPath(fieldName, rs.separator), "\x00"))
+}
+
+// matchRule returns the hush type of the rule matching fieldName, or "" if none does.
+func (o *hushOptions) matchRule(fieldName string) string {
+	return o.ruleSet.match(fieldName)
+}
+
+// globMatch reports whether s matches pattern, where "*" matches any sequence of characters.
+func globMatch(pattern, s string) bool {
+	// Backtrack to the last star on mismatch.
+	star, next := -1, 0
+	p, i := 0, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, next = p, i
+			p++
+		case p < len(pattern) && pattern[p] == s[i]:
+			p++
+			i++
+		case star >= 0:
+			next++
+			p, i = star+1, next
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
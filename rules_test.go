@@ -0,0 +1,127 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRuleSetMatch(t *testing.T) {
+	rs := compileRules([]Rule{
+		{Path: "user.email", Type: TagHide},
+		{Path: "*.password", Type: TagHide},
+		{Path: "*Token", Type: TagMask},
+		{Path: "headers[authorization]", Type: TagRemove},
+		{Path: "*.secret*", Type: TagMask},
+		{Path: "*.secretKey", Type: TagHide},
+		{Regexp: regexp.MustCompile(`^Items\[\d+\]\.Code$`), Type: TagMask},
+		{Path: "user.email", Type: TagMask},
+	}, ".")
+
+	tests := []struct {
+		fieldName string
+		want      string
+	}{
+		{"user.email", "mask"},
+		{"User.Email", "mask"},
+		{"user.password", "hide"},
+		{"password", ""},
+		{"AccessToken", "mask"},
+		{"auth.RefreshToken", "mask"},
+		{"headers[Authorization]", "remove"},
+		{"Headers.Authorization", "remove"},
+		{"config.SecretKey", "hide"},
+		{"config.SecretValue", "mask"},
+		{"Items[3].Code", "mask"},
+		{"items[3].code", ""},
+		{"user.name", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			if got := rs.match(tt.fieldName); got != tt.want {
+				t.Errorf("match(%q) = %q, want %q", tt.fieldName, got, tt.want)
+			}
+		})
+	}
+
+	var empty *ruleSet
+	if got := empty.match("anything"); got != "" {
+		t.Errorf("nil ruleSet match() = %q, want empty", got)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"a*c", "abbbc", true},
+		{"a*c", "abbb", false},
+		{"*token", "accesstoken", true},
+		{"*a*b*", "xaybz", true},
+		{"abc", "abc", true},
+		{"abc", "abd", false},
+	}
+
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestHushWithFieldRules(t *testing.T) {
+	type credentials struct {
+		Username    string
+		Password    string `hush:"mask"`
+		AccessToken string
+	}
+	type request struct {
+		Headers     map[string]string
+		Credentials credentials
+		Scopes      []string
+	}
+
+	input := request{
+		Headers:     map[string]string{"Authorization": "Bearer abc", "Accept": "*/*"},
+		Credentials: credentials{Username: "john", Password: "secret123", AccessToken: "tok"},
+		Scopes:      []string{"read", "write"},
+	}
+	options := []interface{}{
+		WithFieldRules(map[string]HushType{
+			"*.password":             TagHide,
+			"*Token":                 TagHide,
+			"headers[authorization]": TagRemove,
+			"Scopes[1]":              TagMask,
+		}),
+	}
+
+	got, err := NewHush().Hush(context.Background(), input, options...)
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{
+		{"Credentials.AccessToken", "HIDDEN"},
+		{"Credentials.Password", "s*******3"},
+		{"Credentials.Username", "john"},
+		{"Headers[Accept]", "*/*"},
+		{"Scopes[0]", "read"},
+		{"Scopes[1]", "*****"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hush() = %v, want %v", got, want)
+	}
+
+	sanitized, err := Sanitize(context.Background(), input, options...)
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if _, ok := sanitized.Headers["Authorization"]; ok || sanitized.Credentials.AccessToken != HiddenValue || sanitized.Scopes[1] != "*****" {
+		t.Errorf("Sanitize() = %+v, want field rules applied", sanitized)
+	}
+}
@@ -0,0 +1,47 @@
+package hush
+
+import (
+	"context"
+	"fmt"
+)
+
+// SafeFormatter is a value that formats as a hushed value, see Safe.
+type SafeFormatter interface {
+	fmt.Formatter
+	fmt.Stringer
+}
+
+// safeValue wraps a value so that it is hushed when it is formatted.
+type safeValue struct {
+	v    interface{}
+	args []interface{}
+}
+
+// Safe returns a value that formats as v once hushed, so that it can be passed to
+// fmt.Printf, log.Printf and the like:
+//
+//	log.Printf("loaded config %+v", hush.Safe(cfg))
+//
+// It accepts the same arguments as Husher.Hush. v is sanitized when it is formatted, see
+// Sanitize, and the copy is formatted with the same verb and flags, so %v, %+v, %#v and
+// %s keep the shape of Go's native formatting. Hidden and removed values that can't hold
+// a string are formatted as their zero value, and so are unexported fields unless
+// WithPrivateFields(true) is set.
+func Safe(v interface{}, args ...interface{}) SafeFormatter {
+	return safeValue{v: v, args: args}
+}
+
+// Format implements fmt.Formatter.
+func (s safeValue) Format(f fmt.State, verb rune) {
+	sanitized, err := Sanitize(context.Background(), s.v, s.args...)
+	if err != nil {
+		fmt.Fprint(f, "!ERROR: "+err.Error())
+		return
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), sanitized)
+}
+
+// String implements fmt.Stringer, formatting the value like %v.
+func (s safeValue) String() string {
+	return fmt.Sprint(s)
+}
@@ -0,0 +1,98 @@
+package hush
+
+import (
+	"fmt"
+	"testing"
+)
+
+type safeConfig struct {
+	Host     string
+	Port     int
+	Password string `hush:"hide"`
+	Token    string `hush:"mask"`
+	Pin      int    `hush:"hide"`
+	Replicas []string
+	Admin    errUser
+}
+
+func TestSafe(t *testing.T) {
+	cfg := safeConfig{
+		Host:     "db.internal",
+		Port:     5432,
+		Password: "hunter2",
+		Token:    "secret-token",
+		Pin:      1234,
+		Replicas: []string{"db-1", "db-2"},
+		Admin:    errUser{Name: "John", Email: "john@example.com", Password: "hunter2"},
+	}
+	// want is cfg as hushed, so that Safe must format like fmt formats it.
+	want := cfg
+	want.Password, want.Token, want.Pin = HiddenValue, "s**********n", 0
+	want.Admin = errUser{Name: "John", Email: "j**************m", Password: HiddenValue}
+
+	tests := []struct {
+		name   string
+		format string
+		value  interface{}
+		args   []interface{}
+		want   interface{}
+	}{
+		{"Value", "%v", cfg, nil, want},
+		{"Field names", "%+v", cfg, nil, want},
+		{"Go syntax", "%#v", cfg, nil, want},
+		{"String verb", "%s", cfg, nil, want},
+		{"Pointer", "%+v", &cfg, nil, &want},
+		{"Width", "%-20v|", "hunter2", []interface{}{TagHide}, HiddenValue},
+		{"Slice", "%q", []string{"hunter2", "public"}, []interface{}{TagMask}, []string{"*******", "******"}},
+		{"Nil", "%v", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fmt.Sprintf(tt.format, Safe(tt.value, tt.args...))
+			if want := fmt.Sprintf(tt.format, tt.want); got != want {
+				t.Errorf("Sprintf(%q, Safe()) = %q, want %q", tt.format, got, want)
+			}
+		})
+	}
+
+	if got, want := Safe(&cfg).String(), fmt.Sprint(&want); got != want {
+		t.Errorf("Safe().String() = %q, want %q", got, want)
+	}
+	if cfg.Password != "hunter2" || cfg.Admin.Email != "john@example.com" {
+		t.Errorf("Safe() modified its argument")
+	}
+}
+
+func TestSafeUnexportedFields(t *testing.T) {
+	type config struct {
+		Host     string
+		password string `hush:"hide"`
+		token    string
+	}
+	cfg := config{Host: "db.internal", password: "hunter2", token: "secret-token"}
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want string
+	}{
+		{"Skipped", nil, "{Host:db.internal password: token:}"},
+		{"Private fields", []interface{}{WithPrivateFields(true)}, "{Host:db.internal password:HIDDEN token:secret-token}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf("%+v", Safe(cfg, tt.args...)); got != tt.want {
+				t.Errorf("Sprintf(%q, Safe()) = %q, want %q", "%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeInvalidArguments(t *testing.T) {
+	got := fmt.Sprintf("%v", Safe("hunter2", HushType("unknown")))
+	if got == "hunter2" || got[:7] != "!ERROR:" {
+		t.Errorf("Sprintf(Safe()) with an invalid hush type = %q, want an error", got)
+	}
+}
@@ -0,0 +1,246 @@
+package hush
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Sanitize returns a deep copy of v with the hush tags applied in place.
+// It accepts the same arguments as Husher.Hush and follows the same traversal rules:
+// masked and hidden strings are replaced by their hushed value, masked or hidden
+// values that cannot hold a string and removed fields are set to their zero value,
+// and removed map entries are deleted. Values of a Redactor are replaced by the value
+// it returns when their kind is string, and zeroed otherwise.
+// Unexported fields are set to their zero value unless WithPrivateFields(true) is set,
+// in which case they are sanitized like exported ones.
+// The input value is never modified.
+func Sanitize[T any](ctx context.Context, v T, args ...interface{}) (T, error) {
+	opts := newHushOptions(args)
+	if err := opts.validate(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return v, nil
+	}
+
+	ht := &hushType{}
+	sanitized, err := ht.sanitizeValue(ctx, opts.prefix, reflect.StructField{}, rv, opts, "", 0, nil)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return sanitized.Interface().(T), nil
+}
+
+// sanitizeValue returns a copy of value with the hush tags applied.
+// It mirrors processValue, but builds a value of the same type instead of rows.
+// Pointers leading back to an enclosing value point to its copy, so cycles are kept.
+func (ht *hushType) sanitizeValue(ctx context.Context, fieldName string, field reflect.StructField, value reflect.Value, opts *hushOptions, inheritedTag string, depth int, visited *visit) (reflect.Value, error) {
+	if depth > opts.depthLimit() {
+		return reflect.Zero(value.Type()), nil
+	}
+
+	hushTag := field.Tag.Get("hush")
+	if hushTag == "" {
+		hushTag = opts.matchRule(fieldName)
+	}
+	if hushTag == "" {
+		hushTag = inheritedTag
+	}
+	if err := opts.checkHushTag(fieldName, hushTag); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if tagAction(hushTag) == TagRemove {
+		return reflect.Zero(value.Type()), nil
+	}
+
+	if leaf, leafTag, ok := resolveLeaf(ctx, value); ok {
+		if hushTag == "" {
+			hushTag = string(leafTag)
+		}
+		if err := opts.checkHushTag(fieldName, hushTag); err != nil {
+			return reflect.Value{}, err
+		}
+		if tagAction(hushTag) == TagRemove {
+			return reflect.Zero(value.Type()), nil
+		}
+		return sanitizeLeaf(value, leaf, resolveHushTag(leaf, hushTag, opts), opts), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return ht.sanitizeStruct(ctx, fieldName, value, opts, depth+1, visited)
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value, nil
+		}
+		if ancestor, ok := visited.find(value); ok {
+			return ancestor.copy, nil
+		}
+		ptr := reflect.New(value.Type().Elem())
+		v := newVisit(value, fieldName, visited)
+		v.copy = ptr
+		elem, err := ht.sanitizeValue(ctx, fieldName, reflect.StructField{}, value.Elem(), opts, hushTag, depth+1, v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Interface:
+		if value.IsNil() {
+			return value, nil
+		}
+		elem, err := ht.sanitizeValue(ctx, fieldName, reflect.StructField{}, value.Elem(), opts, hushTag, depth+1, visited)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst := reflect.New(value.Type()).Elem()
+		dst.Set(elem)
+		return dst, nil
+	case reflect.Slice:
+		if value.IsNil() {
+			return value, nil
+		}
+		return ht.sanitizeElements(ctx, fieldName, reflect.MakeSlice(value.Type(), value.Len(), value.Len()), value, opts, hushTag, depth+1, visited)
+	case reflect.Array:
+		return ht.sanitizeElements(ctx, fieldName, reflect.New(value.Type()).Elem(), value, opts, hushTag, depth+1, visited)
+	case reflect.Map:
+		return ht.sanitizeMap(ctx, fieldName, value, opts, hushTag, depth+1, visited)
+	default:
+		return sanitizeNonComposite(value, hushTag, opts), nil
+	}
+}
+
+// sanitizeStruct copies the struct and replaces every processed field with its sanitized copy.
+func (ht *hushType) sanitizeStruct(ctx context.Context, prefix string, rv reflect.Value, opts *hushOptions, depth int, visited *visit) (reflect.Value, error) {
+	plan := planFor(rv.Type())
+	dst := reflect.New(rv.Type()).Elem()
+	dst.Set(rv)
+
+	for i := range plan.fields {
+		select {
+		case <-ctx.Done():
+			return reflect.Value{}, ctx.Err()
+		default:
+		}
+
+		field := plan.fields[i].field
+		fieldValue := dst.Field(plan.fields[i].index)
+
+		if !plan.fields[i].exported {
+			fieldValue = exposeField(fieldValue)
+			if !opts.includePrivate {
+				// Skipped fields are zeroed rather than copied, so their values can't leak
+				// through formatting and the copy shares no memory with the input.
+				fieldValue.Set(reflect.Zero(field.Type))
+				continue
+			}
+		}
+
+		sanitized, err := ht.sanitizeValue(ctx, buildFieldName(prefix, escapeFieldName(field.Name, opts.separator), opts.separator), field, fieldValue, opts, "", depth, visited)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		fieldValue.Set(sanitized)
+	}
+
+	return dst, nil
+}
+
+// sanitizeElements fills dst with the sanitized elements of the slice or array src.
+func (ht *hushType) sanitizeElements(ctx context.Context, fieldName string, dst, src reflect.Value, opts *hushOptions, hushTag string, depth int, visited *visit) (reflect.Value, error) {
+	for i := 0; i < src.Len(); i++ {
+		select {
+		case <-ctx.Done():
+			return reflect.Value{}, ctx.Err()
+		default:
+		}
+
+		elem, err := ht.sanitizeValue(ctx, fmt.Sprintf("%s[%d]", fieldName, i), reflect.StructField{}, src.Index(i), opts, hushTag, depth, visited)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.Index(i).Set(elem)
+	}
+	return dst, nil
+}
+
+// sanitizeMap builds a new map holding the sanitized values of src under the original keys.
+func (ht *hushType) sanitizeMap(ctx context.Context, fieldName string, src reflect.Value, opts *hushOptions, hushTag string, depth int, visited *visit) (reflect.Value, error) {
+	if src.IsNil() {
+		return src, nil
+	}
+
+	dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+	iter := src.MapRange()
+	for iter.Next() {
+		select {
+		case <-ctx.Done():
+			return reflect.Value{}, ctx.Err()
+		default:
+		}
+
+		mapFieldName := keyPath(fieldName, fmt.Sprint(iter.Key()))
+		if tagAction(opts.matchRule(mapFieldName)) == TagRemove {
+			continue // Removed map entries are dropped rather than zeroed
+		}
+		elem, err := ht.sanitizeValue(ctx, mapFieldName, reflect.StructField{}, iter.Value(), opts, hushTag, depth, visited)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.SetMapIndex(iter.Key(), elem)
+	}
+	return dst, nil
+}
+
+// sanitizeNonComposite applies the hush tag to a leaf value.
+// Strings are replaced by their hushed value, other kinds are zeroed when they are hushed.
+func sanitizeNonComposite(value reflect.Value, hushTag string, opts *hushOptions) reflect.Value {
+	if value.Kind() == reflect.String {
+		hushed := hushString(value.String(), hushTag, opts)
+		if hushed == value.String() {
+			return value
+		}
+		dst := reflect.New(value.Type()).Elem()
+		dst.SetString(hushed)
+		return dst
+	}
+
+	if !isBasicTypeKind(value.Kind()) {
+		return value
+	}
+
+	hushTag = resolveHushTag(convertNonCompositeToString(value), hushTag, opts)
+	if isHushingTag(hushTag) {
+		return reflect.Zero(value.Type())
+	}
+	return value
+}
+
+// sanitizeLeaf applies the resolved hush tag to a value presenting itself as the single
+// value leaf. Values of Redactors, and hushed values, are written in place of the value
+// when its kind holds a string and zeroed otherwise. Other values are kept as is.
+func sanitizeLeaf(value reflect.Value, leaf, hushTag string, opts *hushOptions) reflect.Value {
+	redactor := methodsFor(value.Type()).redactor != receiverNone
+	if !redactor && !isHushingTag(hushTag) {
+		return value
+	}
+	if value.Kind() != reflect.String {
+		return reflect.Zero(value.Type())
+	}
+	dst := reflect.New(value.Type()).Elem()
+	dst.SetString(applyHushTag(leaf, hushTag, opts))
+	return dst
+}
+
+// exposeField returns a settable view of an unexported field of an addressable struct.
+func exposeField(field reflect.Value) reflect.Value {
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
@@ -0,0 +1,180 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type sanitizeAddress struct {
+	Street string `hush:"mask"`
+	City   string
+}
+
+type sanitizeUser struct {
+	Name     string
+	Password string `hush:"hide"`
+	PIN      int    `hush:"mask"`
+	Email    string `hush:"remove"`
+	Address  *sanitizeAddress
+	Tokens   []string          `hush:"mask"`
+	Labels   map[string]string `hush:"hide"`
+	secret   string            `hush:"hide"`
+	history  []string
+}
+
+func TestSanitize(t *testing.T) {
+	input := sanitizeUser{
+		Name:     "John",
+		Password: "secret123",
+		PIN:      1234,
+		Email:    "john@example.com",
+		Address:  &sanitizeAddress{Street: "Main", City: "Anytown"},
+		Tokens:   []string{"abc", "defgh"},
+		Labels:   map[string]string{"role": "admin"},
+		secret:   "private",
+		history:  []string{"hunter1"},
+	}
+
+	tests := []struct {
+		name    string
+		options []interface{}
+		want    sanitizeUser
+	}{
+		{
+			name: "Without private fields",
+			want: sanitizeUser{
+				Name:     "John",
+				Password: HiddenValue,
+				Address:  &sanitizeAddress{Street: "****", City: "Anytown"},
+				Tokens:   []string{"***", "*****"},
+				Labels:   map[string]string{"role": HiddenValue},
+			},
+		},
+		{
+			name:    "With private fields",
+			options: []interface{}{WithPrivateFields(true)},
+			want: sanitizeUser{
+				Name:     "John",
+				Password: HiddenValue,
+				Address:  &sanitizeAddress{Street: "****", City: "Anytown"},
+				Tokens:   []string{"***", "*****"},
+				Labels:   map[string]string{"role": HiddenValue},
+				secret:   HiddenValue,
+				history:  []string{"hunter1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(context.Background(), input, tt.options...)
+			if err != nil {
+				t.Fatalf("Sanitize() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sanitize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if input.Password != "secret123" || input.Address.Street != "Main" || input.Tokens[0] != "abc" || input.Labels["role"] != "admin" {
+		t.Errorf("Sanitize() modified its input: %+v", input)
+	}
+}
+
+func TestSanitizePointerAndString(t *testing.T) {
+	ptr, err := Sanitize(context.Background(), &sanitizeAddress{Street: "Main", City: "Anytown"})
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if want := (&sanitizeAddress{Street: "****", City: "Anytown"}); !reflect.DeepEqual(ptr, want) {
+		t.Errorf("Sanitize() = %+v, want %+v", ptr, want)
+	}
+
+	str, err := Sanitize(context.Background(), "sensitive", TagMask)
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if str != "s*******e" {
+		t.Errorf("Sanitize() = %v, want %v", str, "s*******e")
+	}
+
+	var nilValue interface{}
+	if got, err := Sanitize(context.Background(), nilValue); err != nil || got != nil {
+		t.Errorf("Sanitize(nil) = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestSanitizeInterfaceFields(t *testing.T) {
+	type event struct {
+		Payload interface{} `hush:"hide"`
+		Address interface{}
+		Extra   map[string]interface{}
+		Empty   interface{}
+	}
+
+	input := event{
+		Payload: "secret",
+		Address: sanitizeAddress{Street: "Main", City: "Anytown"},
+		Extra:   map[string]interface{}{"street": "Main", "count": 3},
+	}
+	got, err := Sanitize(context.Background(), input, Rule{Path: "Extra[street]", Type: TagMask})
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	want := event{
+		Payload: HiddenValue,
+		Address: sanitizeAddress{Street: "****", City: "Anytown"},
+		Extra:   map[string]interface{}{"street": "****", "count": 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sanitize() = %+v, want %+v", got, want)
+	}
+	if input.Extra["street"] != "Main" {
+		t.Errorf("Sanitize() modified the input: %+v", input)
+	}
+}
+
+type sanitizeAccountID string
+
+func (a sanitizeAccountID) HushValue(ctx context.Context) (string, HushType) {
+	return string(a[:4]) + "****", ""
+}
+
+type sanitizeAccountNumber int
+
+func (n sanitizeAccountNumber) HushValue(ctx context.Context) (string, HushType) {
+	return "****", ""
+}
+
+func TestSanitizeLeafValues(t *testing.T) {
+	type account struct {
+		ID     sanitizeAccountID
+		Number sanitizeAccountNumber
+		Card   cardNumber
+		Shown  cardNumber `hush:"hide,value=-"`
+		Level  level
+		Hidden level `hush:"hide"`
+		Opaque opaque
+	}
+
+	input := account{ID: "ACC-123456", Number: 42, Card: "4111", Shown: "4111", Level: 2, Hidden: 3, Opaque: opaque{id: 1}}
+	got, err := Sanitize(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	want := account{ID: "ACC-****", Card: "****", Shown: "-", Level: 2, Opaque: opaque{id: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sanitize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSanitizeCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Sanitize(ctx, sanitizeUser{Name: "John"}); err != context.Canceled {
+		t.Errorf("Sanitize() error = %v, want %v", err, context.Canceled)
+	}
+}
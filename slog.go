@@ -0,0 +1,265 @@
+package hush
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// slogValue wraps a value so that it is hushed when it is logged with log/slog.
+type slogValue struct {
+	v    interface{}
+	args []interface{}
+}
+
+// SlogValue returns a slog.LogValuer that hushes v when it is logged.
+// It accepts the same arguments as Husher.Hush. Structs are logged as nested groups
+// whose keys, joined by the separator, match the field names returned by Hush.
+func SlogValue(v interface{}, args ...interface{}) slog.LogValuer {
+	return slogValue{v: v, args: args}
+}
+
+// LogValue implements slog.LogValuer.
+func (s slogValue) LogValue() slog.Value {
+	return hushSlogValue(context.Background(), s.v, newHushOptions(s.args))
+}
+
+// hushSlogValue hushes v and converts the resulting rows into a slog.Value.
+func hushSlogValue(ctx context.Context, v interface{}, opts *hushOptions) slog.Value {
+	if err := opts.validate(); err != nil {
+		return slog.StringValue("!ERROR: " + err.Error())
+	}
+
+	ht := &hushType{}
+	fields, err := ht.processValue(ctx, opts.prefix, reflect.StructField{}, reflect.ValueOf(v), opts, origin{}, 0, nil)
+	if err != nil {
+		return slog.StringValue("!ERROR: " + err.Error())
+	}
+
+	rows := fieldRows(fields)
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		return slog.StringValue(rows[0][0])
+	}
+
+	return slog.GroupValue(fieldNodeAttrs(buildFieldTree(rows, opts.separator))...)
+}
+
+// fieldNodeAttrs converts the children of a field tree node into slog attributes.
+func fieldNodeAttrs(node *fieldNode) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(node.children))
+	for _, child := range node.children {
+		if child.leaf {
+			attrs = append(attrs, slog.String(child.name, child.value))
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: child.name, Value: slog.GroupValue(fieldNodeAttrs(child)...)})
+	}
+	return attrs
+}
+
+// slogHandler is a slog.Handler that hushes struct attributes before passing them on.
+type slogHandler struct {
+	next slog.Handler
+	opts *hushOptions
+}
+
+// NewSlogHandler returns a slog.Handler that hushes every attribute whose value HasTags
+// reports as needing it before passing the record to next: values with hush tags or
+// Redactors, and any value when field rules or detectors are set. String attributes are
+// masked when one of the detectors recognises them.
+// It accepts the same options as Husher.Hush.
+func NewSlogHandler(next slog.Handler, args ...interface{}) slog.Handler {
+	return &slogHandler{next: next, opts: newHushOptions(args)}
+}
+
+// Enabled implements slog.Handler.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	hushed := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		hushed.AddAttrs(h.hushAttr(ctx, attr))
+		return true
+	})
+	return h.next.Handle(ctx, hushed)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hushed := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		hushed = append(hushed, h.hushAttr(context.Background(), attr))
+	}
+	return &slogHandler{next: h.next.WithAttrs(hushed), opts: h.opts}
+}
+
+// WithGroup implements slog.Handler.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// callOptions returns the options of the handler for hushing a single value, so that
+// the current key is resolved again for every value logged.
+func (h *slogHandler) callOptions() *hushOptions {
+	opts := *h.opts
+	opts.currentKey = &currentKey{}
+	return &opts
+}
+
+// hushAttr hushes the attribute if it needs it, descending into groups.
+func (h *slogHandler) hushAttr(ctx context.Context, attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		hushed := make([]slog.Attr, 0, len(group))
+		for _, a := range group {
+			hushed = append(hushed, h.hushAttr(ctx, a))
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(hushed...)}
+	case slog.KindString:
+		if v := attr.Value.String(); h.opts.detect(v) {
+			return slog.String(attr.Key, hushString(v, "", h.callOptions()))
+		}
+	case slog.KindAny:
+		v := attr.Value.Any()
+		if h.opts.hushesUntagged() || hasTags(v) {
+			return slog.Attr{Key: attr.Key, Value: hushSlogValue(ctx, v, h.callOptions())}
+		}
+	}
+	return attr
+}
+
+// HasTags reports whether hushing v with args may change it, so logging adapters can
+// pass other values on untouched. It reports true when v holds a struct with a hush tag
+// on any of its fields or a Redactor, looking through pointers, slices, arrays, maps and
+// the values stored in interfaces, and for any value when args set field rules or
+// detectors. Values only changed by a handler registered with RegisterTypeHandler are
+// not reported.
+func HasTags(v interface{}, args ...interface{}) bool {
+	if hasTags(v) {
+		return true
+	}
+	return v != nil && len(args) > 0 && newHushOptions(args).hushesUntagged()
+}
+
+// hasTags reports whether v holds hush tags or a Redactor, see HasTags.
+func hasTags(v interface{}) bool {
+	return valueHasTags(reflect.ValueOf(v), 0)
+}
+
+// hushesUntagged reports whether the options may hush values without hush tags,
+// through field rules or detectors.
+func (o *hushOptions) hushesUntagged() bool {
+	return len(o.rules) > 0 || len(o.detectors) > 0
+}
+
+// tagPresence tells whether the values of a type hold hush tags or Redactors.
+type tagPresence int
+
+const (
+	tagsNone    tagPresence = iota
+	tagsFound               // Every value of the type holds some
+	tagsDynamic             // Values of the type hold interfaces, which may hold some
+)
+
+// hushTagCache caches the tagPresence of types, keyed by reflect.Type.
+var hushTagCache sync.Map
+
+// hasHushTags reports whether t is a struct (or a pointer to one) with a hush tag
+// on any of its fields, including fields of nested structs, slices and maps, or a
+// Redactor.
+func hasHushTags(t reflect.Type) bool {
+	return tagPresenceOf(t) == tagsFound
+}
+
+// tagPresenceOf returns the tagPresence of t.
+func tagPresenceOf(t reflect.Type) tagPresence {
+	if t == nil {
+		return tagsNone
+	}
+	if cached, ok := hushTagCache.Load(t); ok {
+		return cached.(tagPresence)
+	}
+
+	found := searchHushTags(t, make(map[reflect.Type]bool))
+	hushTagCache.Store(t, found)
+	return found
+}
+
+// searchHushTags walks t looking for hush tags and Redactors, skipping types already
+// being visited.
+func searchHushTags(t reflect.Type, visiting map[reflect.Type]bool) tagPresence {
+	if visiting[t] {
+		return tagsNone
+	}
+	visiting[t] = true
+
+	if methodsFor(t).redactor != receiverNone {
+		return tagsFound
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return tagsDynamic
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return searchHushTags(t.Elem(), visiting)
+	case reflect.Struct:
+		presence := tagsNone
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Tag.Get("hush") != "" {
+				return tagsFound
+			}
+			switch searchHushTags(field.Type, visiting) {
+			case tagsFound:
+				return tagsFound
+			case tagsDynamic:
+				presence = tagsDynamic
+			}
+		}
+		return presence
+	}
+	return tagsNone
+}
+
+// valueHasTags reports whether v holds hush tags or a Redactor, looking into the values
+// stored in interfaces when the type of v alone doesn't tell.
+func valueHasTags(v reflect.Value, depth int) bool {
+	if !v.IsValid() || depth > maxRecursionDepth {
+		return false
+	}
+	switch tagPresenceOf(v.Type()) {
+	case tagsFound:
+		return true
+	case tagsNone:
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && valueHasTags(v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if valueHasTags(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if valueHasTags(iter.Value(), depth+1) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if valueHasTags(v.Field(i), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package hush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+type slogAddress struct {
+	Street string `hush:"mask"`
+	City   string
+}
+
+type slogUser struct {
+	Name     string
+	Password string `hush:"hide"`
+	Address  slogAddress
+	Tags     []string
+}
+
+type slogPlain struct {
+	Name string
+}
+
+type slogEvent struct {
+	Name    string
+	Payload interface{}
+}
+
+type slogAccountID string
+
+func (id slogAccountID) HushValue(ctx context.Context) (string, HushType) {
+	return "acct_" + string(id[len(id)-4:]), ""
+}
+
+func decodeSlogJSON(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON log line %q: %v", buf.String(), err)
+	}
+	delete(got, "time")
+	delete(got, "level")
+	delete(got, "msg")
+	return got
+}
+
+func TestSlogValue(t *testing.T) {
+	user := slogUser{
+		Name:     "John",
+		Password: "secret123",
+		Address:  slogAddress{Street: "Main", City: "Anytown"},
+		Tags:     []string{"a", "b"},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("user", "user", SlogValue(user))
+
+	want := map[string]interface{}{
+		"user": map[string]interface{}{
+			"Address":  map[string]interface{}{"City": "Anytown", "Street": "****"},
+			"Name":     "John",
+			"Password": "HIDDEN",
+			"Tags[0]":  "a",
+			"Tags[1]":  "b",
+		},
+	}
+	if got := decodeSlogJSON(t, &buf); !reflect.DeepEqual(got, want) {
+		t.Errorf("SlogValue() logged %v, want %v", got, want)
+	}
+}
+
+func TestSlogValueString(t *testing.T) {
+	got := SlogValue("sensitive", TagMask).LogValue()
+	if got.Kind() != slog.KindString || got.String() != "s*******e" {
+		t.Errorf("SlogValue().LogValue() = %v, want %v", got, "s*******e")
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.With("plain", slogPlain{Name: "John"}).Info("user",
+		"user", &slogUser{Name: "John", Password: "secret123"},
+		slog.Group("request", "address", slogAddress{Street: "Main"}),
+		"count", 1,
+	)
+
+	got := decodeSlogJSON(t, &buf)
+	want := map[string]interface{}{
+		"plain": map[string]interface{}{"Name": "John"},
+		"user": map[string]interface{}{
+			"Address":  map[string]interface{}{"City": "", "Street": ""},
+			"Name":     "John",
+			"Password": "HIDDEN",
+		},
+		"request": map[string]interface{}{
+			"address": map[string]interface{}{"City": "", "Street": "****"},
+		},
+		"count": float64(1),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewSlogHandler() logged %v, want %v", got, want)
+	}
+}
+
+func TestSlogHandlerUntagged(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []interface{}
+		value interface{}
+		want  interface{}
+	}{
+		{
+			name:  "Tagged struct in an interface",
+			value: slogEvent{Name: "signup", Payload: slogAddress{Street: "Main"}},
+			want:  map[string]interface{}{"Name": "signup", "Payload": map[string]interface{}{"City": "", "Street": "****"}},
+		},
+		{
+			name:  "Redactor",
+			value: slogAccountID("8812345678"),
+			want:  "acct_5678",
+		},
+		{
+			name:  "Field rule",
+			args:  []interface{}{Rule{Path: "Name", Type: TagHide}},
+			value: slogPlain{Name: "John"},
+			want:  map[string]interface{}{"Name": HiddenValue},
+		},
+		{
+			name:  "Detector",
+			args:  []interface{}{WithDetectors(EmailDetector)},
+			value: "john@example.com",
+			want:  "j**************m",
+		},
+		{
+			name:  "Untagged value in an interface",
+			value: slogEvent{Name: "signup", Payload: slogPlain{Name: "John"}},
+			want:  map[string]interface{}{"Name": "signup", "Payload": map[string]interface{}{"Name": "John"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil), tt.args...))
+			logger.Info("event", "value", tt.value)
+
+			if got := decodeSlogJSON(t, &buf)["value"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewSlogHandler() logged %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasHushTags(t *testing.T) {
+	type recursive struct {
+		Next   *recursive
+		Secret string `hush:"hide"`
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{"Tagged struct", slogAddress{}, true},
+		{"Nested tagged struct", &slogUser{}, true},
+		{"Plain struct", slogPlain{}, false},
+		{"Slice of tagged structs", []slogAddress{}, true},
+		{"Recursive struct", &recursive{}, true},
+		{"String", "value", false},
+		{"Nil", nil, false},
+		{"Redactor", slogAccountID("8812345678"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasHushTags(reflect.TypeOf(tt.value)); got != tt.want {
+				t.Errorf("hasHushTags() = %v, want %v", got, tt.want)
+			}
+			if got := HasTags(tt.value); got != tt.want {
+				t.Errorf("HasTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		args  []interface{}
+		want  bool
+	}{
+		{"Tagged struct in an interface", slogEvent{Payload: &slogAddress{}}, nil, true},
+		{"Tagged struct in a map of interfaces", map[string]interface{}{"a": 1, "b": []interface{}{slogAddress{}}}, nil, true},
+		{"Untagged struct in an interface", slogEvent{Payload: slogPlain{}}, nil, false},
+		{"Nil interface", slogEvent{}, nil, false},
+		{"Field rule", slogPlain{}, []interface{}{Rule{Path: "Name", Type: TagHide}}, true},
+		{"Detectors", slogPlain{}, []interface{}{WithDetectors(EmailDetector)}, true},
+		{"Other options", slogPlain{}, []interface{}{WithSeparator("_")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasTags(tt.value, tt.args...); got != tt.want {
+				t.Errorf("HasTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package hush
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// tagDirective is a parsed hush tag such as "mask,keep=4,char=#,from=end".
+//
+// The first element is the action, the following ones are its parameters:
+//
+//	mask      keep=N (characters left visible), char=C (mask character, default "*"),
+//	          from=start|end (side the visible characters are kept on, default end).
+//	          Without parameters the mask function set with WithMaskFunc is used.
+//	hide      value=S (replacement, default HIDDEN)
+//	truncate  max=N (required, number of characters kept before "...")
+//	hash      prefix=S (token prefix, default set with WithHashPrefix)
+//	remove, encrypt
+type tagDirective struct {
+	action HushType
+
+	// mask
+	customMask bool
+	keep       int
+	maskChar   string
+	fromStart  bool
+
+	// hide
+	value string
+
+	// truncate
+	max int
+
+	// hash
+	prefix    string
+	hasPrefix bool
+}
+
+// tagParameters lists the parameters accepted by every action.
+var tagParameters = map[HushType][]string{
+	TagMask:     {"keep", "char", "from"},
+	TagHide:     {"value"},
+	TagRemove:   nil,
+	TagHash:     {"prefix"},
+	TagEncrypt:  nil,
+	TagTruncate: {"max"},
+}
+
+// tagCacheEntry is the cached result of parsing a hush tag.
+type tagCacheEntry struct {
+	directive *tagDirective
+	err       error
+}
+
+// tagCache caches parsed hush tags, keyed by the raw tag.
+var tagCache sync.Map
+
+// parseHushTag parses a hush tag, caching the result. An empty tag yields a directive
+// without an action.
+func parseHushTag(tag string) (*tagDirective, error) {
+	if cached, ok := tagCache.Load(tag); ok {
+		entry := cached.(tagCacheEntry)
+		return entry.directive, entry.err
+	}
+
+	directive, err := parseTagDirective(tag)
+	tagCache.Store(tag, tagCacheEntry{directive: directive, err: err})
+	return directive, err
+}
+
+// parseTagDirective parses a hush tag without the cache.
+func parseTagDirective(tag string) (*tagDirective, error) {
+	parts := strings.Split(tag, ",")
+	d := &tagDirective{
+		action:   HushType(strings.TrimSpace(parts[0])),
+		maskChar: "*",
+		value:    HiddenValue,
+	}
+	if d.action == "" {
+		if len(parts) > 1 {
+			return nil, fmt.Errorf("invalid hush tag %q: missing directive", tag)
+		}
+		return d, nil
+	}
+
+	allowed, ok := tagParameters[d.action]
+	if !ok {
+		return nil, fmt.Errorf("invalid hush tag %q: unknown directive %q", tag, d.action)
+	}
+
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !containsString(allowed, key) {
+			return nil, fmt.Errorf("invalid hush tag %q: unknown parameter %q for %s", tag, key, d.action)
+		}
+
+		var err error
+		switch key {
+		case "keep":
+			d.customMask = true
+			d.keep, err = parseTagInt(key, value)
+		case "char":
+			d.customMask = true
+			if utf8.RuneCountInString(value) != 1 {
+				err = fmt.Errorf("char must be a single character, got %q", value)
+			}
+			d.maskChar = value
+		case "from":
+			d.customMask = true
+			switch value {
+			case "start":
+				d.fromStart = true
+			case "end":
+				d.fromStart = false
+			default:
+				err = fmt.Errorf("from must be start or end, got %q", value)
+			}
+		case "value":
+			d.value = value
+		case "max":
+			d.max, err = parseTagInt(key, value)
+		case "prefix":
+			d.prefix, d.hasPrefix = value, true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid hush tag %q: %w", tag, err)
+		}
+	}
+
+	if d.action == TagTruncate && d.max == 0 {
+		return nil, fmt.Errorf("invalid hush tag %q: truncate requires max", tag)
+	}
+	return d, nil
+}
+
+// parseTagInt parses a non-negative integer parameter.
+func parseTagInt(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+	}
+	return n, nil
+}
+
+// tagAction returns the action of a hush tag, or "" if the tag is empty or invalid.
+func tagAction(tag string) HushType {
+	if tag == "" {
+		return ""
+	}
+	d, err := parseHushTag(tag)
+	if err != nil {
+		return ""
+	}
+	return d.action
+}
+
+// validateHushTag returns an error describing why the tag of fieldName is invalid, if it is.
+func validateHushTag(fieldName, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	if _, err := parseHushTag(tag); err != nil {
+		return fieldError(fieldName, err)
+	}
+	return nil
+}
+
+// checkHushTag returns an error if the tag of fieldName is invalid, or if it can't be
+// applied with the options, such as hash without a key or encrypt without a usable
+// key provider.
+func (o *hushOptions) checkHushTag(fieldName, tag string) error {
+	if err := validateHushTag(fieldName, tag); err != nil {
+		return err
+	}
+	switch tagAction(tag) {
+	case TagHash:
+		if len(o.hashKey) == 0 {
+			return fieldError(fieldName, errors.New("hash requires a key, see WithHashKey"))
+		}
+	case TagEncrypt:
+		if _, _, err := o.currentCipher(); err != nil {
+			return fieldError(fieldName, err)
+		}
+	}
+	return nil
+}
+
+// fieldError returns err prefixed with the name of the field it concerns, if any.
+func fieldError(fieldName string, err error) error {
+	if fieldName == "" {
+		return fmt.Errorf("hush: %w", err)
+	}
+	return fmt.Errorf("hush: field %s: %w", fieldName, err)
+}
+
+// apply returns value with the directive applied.
+func (d *tagDirective) apply(value string, opts *hushOptions) string {
+	switch d.action {
+	case TagHide:
+		return d.value
+	case TagMask:
+		if d.customMask {
+			return d.mask(value)
+		}
+		if opts.maskFunc != nil {
+			return opts.maskFunc(value)
+		}
+	case TagHash:
+		if d.hasPrefix {
+			hashOpts := *opts
+			hashOpts.hashPrefix = d.prefix
+			return hashValue(value, &hashOpts)
+		}
+		return hashValue(value, opts)
+	case TagEncrypt:
+		return encryptValue(value, opts)
+	case TagTruncate:
+		runes := []rune(value)
+		if len(runes) > d.max {
+			return string(runes[:d.max]) + "..."
+		}
+	}
+	return value
+}
+
+// mask masks value keeping d.keep characters visible on the configured side.
+// Values no longer than d.keep are masked entirely.
+func (d *tagDirective) mask(value string) string {
+	runes := []rune(value)
+	if len(runes) <= d.keep {
+		return strings.Repeat(d.maskChar, len(runes))
+	}
+
+	masked := strings.Repeat(d.maskChar, len(runes)-d.keep)
+	if d.fromStart {
+		return string(runes[:d.keep]) + masked
+	}
+	return masked + string(runes[len(runes)-d.keep:])
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseHushTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    *tagDirective
+		wantErr bool
+	}{
+		{"Empty", "", &tagDirective{maskChar: "*", value: HiddenValue}, false},
+		{"Bare mask", "mask", &tagDirective{action: TagMask, maskChar: "*", value: HiddenValue}, false},
+		{"Mask with parameters", "mask,keep=4,char=#,from=start", &tagDirective{action: TagMask, customMask: true, keep: 4, maskChar: "#", fromStart: true, value: HiddenValue}, false},
+		{"Hide with value", "hide,value=[REDACTED]", &tagDirective{action: TagHide, maskChar: "*", value: "[REDACTED]"}, false},
+		{"Truncate", "truncate, max=16", &tagDirective{action: TagTruncate, maskChar: "*", value: HiddenValue, max: 16}, false},
+		{"Hash with prefix", "hash,prefix=usr_", &tagDirective{action: TagHash, maskChar: "*", value: HiddenValue, prefix: "usr_", hasPrefix: true}, false},
+		{"Unknown directive", "scramble", nil, true},
+		{"Unknown parameter", "hide,keep=4", nil, true},
+		{"Invalid keep", "mask,keep=four", nil, true},
+		{"Negative keep", "mask,keep=-1", nil, true},
+		{"Invalid char", "mask,char=ab", nil, true},
+		{"Invalid from", "mask,from=middle", nil, true},
+		{"Truncate without max", "truncate", nil, true},
+		{"Parameters without directive", ",keep=4", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHushTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHushTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHushTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagDirectiveApply(t *testing.T) {
+	opts := &hushOptions{maskFunc: defaultMaskFunc, hashKey: []byte("key"), hashPrefix: DefaultHashPrefix}
+
+	tests := []struct {
+		name  string
+		tag   string
+		value string
+		want  string
+	}{
+		{"Default mask", "mask", "sensitive", "s*******e"},
+		{"Keep end", "mask,keep=4", "4111111111111111", "************1111"},
+		{"Keep start with char", "mask,keep=2,char=#,from=start", "secret", "se####"},
+		{"Keep more than length", "mask,keep=8", "short", "*****"},
+		{"Hide", "hide", "secret", HiddenValue},
+		{"Hide with value", "hide,value=[REDACTED]", "secret", "[REDACTED]"},
+		{"Truncate long", "truncate,max=4", "abcdefgh", "abcd..."},
+		{"Truncate short", "truncate,max=16", "abc", "abc"},
+		{"Hash with prefix", "hash,prefix=usr_", "42", "usr_" + hashValue("42", opts)[len(DefaultHashPrefix):]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := parseHushTag(tt.tag)
+			if err != nil {
+				t.Fatalf("parseHushTag() error = %v", err)
+			}
+			if got := d.apply(tt.value, opts); got != tt.want {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHushWithTagParameters(t *testing.T) {
+	type payment struct {
+		Card        string `hush:"mask,keep=4,char=#,from=end"`
+		Holder      string `hush:"hide,value=[REDACTED]"`
+		Description string `hush:"truncate,max=8"`
+	}
+
+	got, err := NewHush().Hush(context.Background(), payment{
+		Card:        "4111111111111111",
+		Holder:      "John Doe",
+		Description: "Monthly subscription",
+	})
+	if err != nil {
+		t.Fatalf("Hush() error = %v", err)
+	}
+	want := [][]string{
+		{"Card", "############1111"},
+		{"Description", "Monthly ..."},
+		{"Holder", "[REDACTED]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hush() = %v, want %v", got, want)
+	}
+}
+
+func TestHushWithInvalidTag(t *testing.T) {
+	type invalid struct {
+		Name string `hush:"scramble"`
+	}
+
+	want := `hush: field Name: invalid hush tag "scramble": unknown directive "scramble"`
+	if _, err := NewHush().Hush(context.Background(), invalid{Name: "John"}); err == nil || err.Error() != want {
+		t.Errorf("Hush() error = %v, want %v", err, want)
+	}
+	if _, err := Sanitize(context.Background(), invalid{Name: "John"}); err == nil || err.Error() != want {
+		t.Errorf("Sanitize() error = %v, want %v", err, want)
+	}
+	if _, err := NewHush().Hush(context.Background(), "John", HushType("mask,keep=x")); err == nil {
+		t.Errorf("Hush() with an invalid hush type error = nil, want an error")
+	}
+	if _, err := HushJSON(context.Background(), []byte(`{"a":"b"}`), Rule{Path: "a", Type: "unknown"}); err == nil {
+		t.Errorf("HushJSON() with an invalid rule error = nil, want an error")
+	}
+}
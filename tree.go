@@ -0,0 +1,51 @@
+package hush
+
+import "context"
+
+// Node is a node of the tree of a hushed value, see HushTree.
+type Node struct {
+	// Name is the part of the node's path between separators, such as "Address" or
+	// "Tags[0]". It is empty for the root.
+	Name string
+	// Value is the hushed value of a leaf.
+	Value string
+	// Leaf reports whether the node holds a value rather than children.
+	Leaf bool
+	// Children are the nodes below an inner node, in the order of the rows.
+	Children []*Node
+}
+
+// HushTree hushes v like Husher.Hush and returns the rows as a tree nested on the
+// separator, so "Address.City" becomes a node "Address" with a leaf "City", the way
+// SlogValue and NestedJSONRenderer nest them. It lets loggers with their own notion of
+// nested objects log hushed values.
+//
+// Values hushed into a single row without a field name, such as strings, are returned
+// as a leaf root.
+func HushTree(ctx context.Context, v interface{}, args ...interface{}) (*Node, error) {
+	opts := newHushOptions(args)
+
+	ht := &hushType{}
+	fields, err := ht.hush(ctx, v, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := fieldRows(fields)
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		return &Node{Value: rows[0][0], Leaf: true}, nil
+	}
+	return newNode(buildFieldTree(rows, opts.separator)), nil
+}
+
+// newNode converts a field tree node and its children into a Node.
+func newNode(fn *fieldNode) *Node {
+	n := &Node{Name: fn.name, Value: fn.value, Leaf: fn.leaf}
+	if len(fn.children) > 0 {
+		n.Children = make([]*Node, len(fn.children))
+		for i, child := range fn.children {
+			n.Children[i] = newNode(child)
+		}
+	}
+	return n
+}
@@ -0,0 +1,86 @@
+package hush
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestHushTree(t *testing.T) {
+	user := slogUser{
+		Name:     "John",
+		Password: "hunter2",
+		Address:  slogAddress{Street: "1 Main Street", City: "Springfield"},
+		Tags:     []string{"admin"},
+	}
+
+	tests := []struct {
+		name    string
+		input   interface{}
+		options []interface{}
+		want    *Node
+	}{
+		{
+			name:  "Struct",
+			input: user,
+			want: &Node{Children: []*Node{
+				{Name: "Address", Children: []*Node{
+					{Name: "City", Value: "Springfield", Leaf: true},
+					{Name: "Street", Value: "1***********t", Leaf: true},
+				}},
+				{Name: "Name", Value: "John", Leaf: true},
+				{Name: "Password", Value: HiddenValue, Leaf: true},
+				{Name: "Tags[0]", Value: "admin", Leaf: true},
+			}},
+		},
+		{
+			name:    "Prefix and separator",
+			input:   slogAddress{Street: "1 Main Street", City: "Springfield"},
+			options: []interface{}{"address", WithSeparator("/")},
+			want: &Node{Children: []*Node{
+				{Name: "address", Children: []*Node{
+					{Name: "City", Value: "Springfield", Leaf: true},
+					{Name: "Street", Value: "1***********t", Leaf: true},
+				}},
+			}},
+		},
+		{
+			name:    "String",
+			input:   "hunter2",
+			options: []interface{}{TagHide},
+			want:    &Node{Value: HiddenValue, Leaf: true},
+		},
+		{
+			name:  "Empty struct",
+			input: struct{}{},
+			want:  &Node{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HushTree(context.Background(), tt.input, tt.options...)
+			if err != nil {
+				t.Fatalf("HushTree() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HushTree() = %s, want %s", formatNode(got), formatNode(tt.want))
+			}
+		})
+	}
+}
+
+// formatNode returns a compact representation of a tree for test failures.
+func formatNode(n *Node) string {
+	if n.Leaf {
+		return n.Name + "=" + n.Value
+	}
+	s := n.Name + "{"
+	for i, child := range n.Children {
+		if i > 0 {
+			s += " "
+		}
+		s += formatNode(child)
+	}
+	return s + "}"
+}
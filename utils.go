@@ -1,6 +1,10 @@
 package hush
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
 	"strings"
 )
 
@@ -19,3 +23,130 @@ func defaultMaskFunc(value string) string {
 	}
 	return string(runes[:1]) + strings.Repeat("*", length-2) + string(runes[length-1:])
 }
+
+// splitFieldName splits a field name built by buildFieldName back into its parts.
+// Separators inside brackets (map keys and indexes) and escaped characters are not
+// treated as boundaries, see Path.
+func splitFieldName(fieldName, separator string) []string {
+	if separator == "" {
+		return []string{fieldName}
+	}
+
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(fieldName); i++ {
+		switch {
+		case fieldName[i] == '\\':
+			i++ // skip the escaped character
+		case fieldName[i] == '[':
+			depth++
+		case fieldName[i] == ']' && depth > 0:
+			depth--
+		case depth == 0 && strings.HasPrefix(fieldName[i:], separator):
+			parts = append(parts, fieldName[start:i])
+			i += len(separator) - 1
+			start = i + 1
+		}
+	}
+	return append(parts, fieldName[start:])
+}
+
+// fieldNode is a node of the tree re-inflated from flat rows.
+// Leaf nodes carry a value, inner nodes carry their children in first-seen order.
+type fieldNode struct {
+	name     string
+	value    string
+	leaf     bool
+	children []*fieldNode
+	index    map[string]*fieldNode
+}
+
+// buildFieldTree re-inflates flat rows into a tree, splitting field names on the separator.
+func buildFieldTree(rows [][]string, separator string) *fieldNode {
+	root := &fieldNode{}
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+
+		node := root
+		for _, part := range splitFieldName(row[0], separator) {
+			node = node.child(part)
+		}
+		node.value = row[1]
+		node.leaf = true
+	}
+	return root
+}
+
+// child returns the child with the given name, creating it if needed.
+func (n *fieldNode) child(name string) *fieldNode {
+	if c, ok := n.index[name]; ok {
+		return c
+	}
+	if n.index == nil {
+		n.index = make(map[string]*fieldNode)
+	}
+	c := &fieldNode{name: name}
+	n.children = append(n.children, c)
+	n.index[name] = c
+	return c
+}
+
+// splitPath splits a field name into its segments: the parts between separators
+// and the contents of every bracket, so "users[0].name" becomes [users 0 name].
+// Escaped characters are unescaped; field names that aren't valid paths, such as
+// rule patterns, are split on brackets and separators as they are.
+func splitPath(fieldName, separator string) []string {
+	if path, err := ParsePath(fieldName, separator); err == nil {
+		segments := make([]string, len(path))
+		for i, s := range path {
+			if s.Kind == SegmentIndex {
+				segments[i] = strconv.Itoa(s.Index)
+			} else {
+				segments[i] = s.Name
+			}
+		}
+		return segments
+	}
+
+	var segments []string
+	for _, part := range splitFieldName(fieldName, separator) {
+		for {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				break
+			}
+			closing := strings.IndexByte(part[open:], ']')
+			if closing < 0 {
+				break
+			}
+			if open > 0 {
+				segments = append(segments, part[:open])
+			}
+			segments = append(segments, part[open+1:open+closing])
+			part = part[open+closing+1:]
+		}
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return segments
+}
+
+// hashTokenLength is the number of hex characters of the HMAC kept in a token.
+const hashTokenLength = 16
+
+// hashValue returns a deterministic token for value: the prefix followed by the first
+// hashTokenLength hex characters of its HMAC-SHA256 under the configured key.
+// Hashed fields without a key are reported by checkHushTag; should one get here anyway,
+// it is hidden rather than tokenized unsafely.
+func hashValue(value string, opts *hushOptions) string {
+	if len(opts.hashKey) == 0 {
+		return HiddenValue
+	}
+
+	mac := hmac.New(sha256.New, opts.hashKey)
+	mac.Write([]byte(value))
+	return opts.hashPrefix + hex.EncodeToString(mac.Sum(nil))[:hashTokenLength]
+}
@@ -1,6 +1,9 @@
 package hush
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestBuildFieldName(t *testing.T) {
 	tests := []struct {
@@ -43,3 +46,93 @@ func TestDefaultMaskFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitFieldName(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		separator string
+		want      []string
+	}{
+		{"Single", "field", ".", []string{"field"}},
+		{"Nested", "parent.child", ".", []string{"parent", "child"}},
+		{"Index", "users[0].name", ".", []string{"users[0]", "name"}},
+		{"Separator in map key", "headers[a.b].value", ".", []string{"headers[a.b]", "value"}},
+		{"Multi-char separator", "parent__child", "__", []string{"parent", "child"}},
+		{"Empty separator", "parent.child", "", []string{"parent.child"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitFieldName(tt.fieldName, tt.separator); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitFieldName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFieldTree(t *testing.T) {
+	rows := [][]string{
+		{"b.y", "1"},
+		{"a", "2"},
+		{"b.x", "3"},
+	}
+
+	root := buildFieldTree(rows, ".")
+	if len(root.children) != 2 || root.children[0].name != "b" || root.children[1].name != "a" {
+		t.Fatalf("buildFieldTree() root children = %+v, want [b a]", root.children)
+	}
+
+	b := root.children[0]
+	if b.leaf || len(b.children) != 2 || b.children[0].name != "y" || b.children[1].value != "3" {
+		t.Errorf("buildFieldTree() b = %+v, want inner node with y=1, x=3", b)
+	}
+	if a := root.children[1]; !a.leaf || a.value != "2" {
+		t.Errorf("buildFieldTree() a = %+v, want leaf with value 2", a)
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		want      []string
+	}{
+		{"Field", "name", []string{"name"}},
+		{"Nested", "user.name", []string{"user", "name"}},
+		{"Index", "users[0].name", []string{"users", "0", "name"}},
+		{"Map key", "headers[Authorization]", []string{"headers", "Authorization"}},
+		{"Map key with separator", "hosts[a.b]", []string{"hosts", "a.b"}},
+		{"Leading index", "[1]", []string{"1"}},
+		{"Multiple indexes", "matrix[1][2]", []string{"matrix", "1", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitPath(tt.fieldName, "."); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	opts := &hushOptions{hashKey: []byte("key"), hashPrefix: DefaultHashPrefix}
+
+	first := hashValue("john@example.com", opts)
+	if len(first) != len(DefaultHashPrefix)+hashTokenLength || first[:len(DefaultHashPrefix)] != DefaultHashPrefix {
+		t.Errorf("hashValue() = %v, want %s followed by %d hex characters", first, DefaultHashPrefix, hashTokenLength)
+	}
+	if again := hashValue("john@example.com", opts); again != first {
+		t.Errorf("hashValue() = %v, want the same token %v for the same input", again, first)
+	}
+	if other := hashValue("jane@example.com", opts); other == first {
+		t.Errorf("hashValue() = %v for different inputs, want different tokens", other)
+	}
+	if otherKey := hashValue("john@example.com", &hushOptions{hashKey: []byte("other")}); otherKey[len(otherKey)-hashTokenLength:] == first[len(first)-hashTokenLength:] {
+		t.Errorf("hashValue() = %v under a different key, want a different token", otherKey)
+	}
+	if noKey := hashValue("john@example.com", &hushOptions{}); noKey != HiddenValue {
+		t.Errorf("hashValue() without key = %v, want %v", noKey, HiddenValue)
+	}
+}